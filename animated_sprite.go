@@ -1,11 +1,14 @@
 package main
 
 import (
+	"towerDefense/assets"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// AnimatedSprite represents a sprite with animation capabilities
+// AnimatedSprite plays a clip from the animation manifest
 type AnimatedSprite struct {
+	clip          *assets.Clip    // Clip being played
 	frames        []*ebiten.Image // Animation frames
 	frameCount    int             // Total number of frames
 	frameDuration float64         // Duration per frame in seconds
@@ -16,28 +19,48 @@ type AnimatedSprite struct {
 	totalDuration float64         // Total animation duration in seconds
 }
 
-func NewAnimatedSprite(frames []*ebiten.Image, animationLength float64, loop bool) *AnimatedSprite {
-	if len(frames) == 0 {
+// NewAnimatedSprite creates a stopped sprite for a clip, or returns nil when there
+// is no clip or it has no frames
+func NewAnimatedSprite(clip *assets.Clip) *AnimatedSprite {
+	if clip == nil || len(clip.Frames) == 0 {
 		return nil
 	}
+	as := &AnimatedSprite{}
+	as.Reset(clip)
+	return as
+}
+
+// Reset turns the sprite into a stopped animation of another clip, so a sprite can
+// be reused instead of allocating another one. A nil clip leaves it with no frames.
+func (as *AnimatedSprite) Reset(clip *assets.Clip) {
+	*as = AnimatedSprite{clip: clip}
+	if clip == nil || len(clip.Frames) == 0 {
+		return
+	}
+	as.frames = clip.Frames
+	as.frameCount = len(clip.Frames)
+	as.frameDuration = clip.Duration / float64(len(clip.Frames))
+	as.loop = clip.Loop
+	as.totalDuration = clip.Duration
+}
+
+// Uses reports whether the sprite plays this clip
+func (as *AnimatedSprite) Uses(clip *assets.Clip) bool {
+	return as.clip == clip
+}
 
-	frameCount := len(frames)
-	frameDuration := animationLength / float64(frameCount)
-
-	return &AnimatedSprite{
-		frames:        frames,
-		frameCount:    frameCount,
-		frameDuration: frameDuration,
-		currentFrame:  0,
-		frameTimer:    0.0,
-		isPlaying:     false,
-		loop:          loop,
-		totalDuration: animationLength,
+// Pivot returns the point of a frame to place on the sprite's position, as
+// fractions of the frame's size
+func (as *AnimatedSprite) Pivot() (float64, float64) {
+	if as.clip == nil {
+		return 0, 0
 	}
+	return as.clip.PivotX, as.clip.PivotY
 }
 
+// Play starts the animation from its first frame. A sprite without frames doesn't play.
 func (as *AnimatedSprite) Play() {
-	as.isPlaying = true
+	as.isPlaying = as.frameCount > 0
 	as.currentFrame = 0
 	as.frameTimer = 0.0
 }
@@ -80,3 +103,41 @@ func (as *AnimatedSprite) Update(deltaTime float64) {
 		}
 	}
 }
+
+// SpritePool recycles sprites for effects that come and go every few ticks, such
+// as projectiles, impacts and weapon fire, so they don't churn the heap
+type SpritePool struct {
+	free []*AnimatedSprite
+}
+
+// NewSpritePool creates an empty pool
+func NewSpritePool() *SpritePool {
+	return &SpritePool{}
+}
+
+// Get returns a playing sprite for the clip, reusing a released one when it can,
+// or nil when there is no clip or it has no frames
+func (p *SpritePool) Get(clip *assets.Clip) *AnimatedSprite {
+	if clip == nil || len(clip.Frames) == 0 {
+		return nil
+	}
+	var as *AnimatedSprite
+	if n := len(p.free); n > 0 {
+		as = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+	} else {
+		as = &AnimatedSprite{}
+	}
+	as.Reset(clip)
+	as.Play()
+	return as
+}
+
+// Put hands a sprite back for reuse. Nil is ignored. The caller must not use the
+// sprite afterwards.
+func (p *SpritePool) Put(as *AnimatedSprite) {
+	if as != nil {
+		p.free = append(p.free, as)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"towerDefense/assets"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Art is the sprites the game draws, looked up by name from the animation
+// manifest once so nothing searches the library while playing. Durations, loops
+// and pivots come from the manifest, so artists can retime a clip without
+// touching the code.
+type Art struct {
+	TrayBackground *ebiten.Image
+	NoneIndicator  *ebiten.Image
+	HealthLeft     *ebiten.Image
+	HealthFill     *ebiten.Image
+	HealthRight    *ebiten.Image
+
+	TowerBuild      *assets.Clip
+	TowerTransition *assets.Clip
+
+	BallistaTower      *ebiten.Image
+	BallistaFire       *assets.Clip
+	BallistaProjectile *assets.Clip
+	BallistaImpact     *assets.Clip
+
+	MagicTower      *ebiten.Image
+	MagicIdle       *assets.Clip
+	MagicAttack     *assets.Clip
+	MagicProjectile *assets.Clip
+	MagicImpact     *assets.Clip
+
+	Firebug CreepArt
+}
+
+// CreepArt is one kind of creep's clips for each state
+type CreepArt struct {
+	Idle, Walk, Death DirectionalClip
+}
+
+// DirectionalClip is a clip drawn facing each way. Left and right share the side clip.
+type DirectionalClip struct {
+	Side, Up, Down *assets.Clip
+}
+
+// For returns the clip for a facing
+func (d DirectionalClip) For(dir Direction) *assets.Clip {
+	switch dir {
+	case DirectionUp:
+		return d.Up
+	case DirectionDown:
+		return d.Down
+	default:
+		return d.Side
+	}
+}
+
+// art is the game's sprites, set by loadArt before any scene is built
+var art *Art
+
+// loadArt looks up every sprite the game uses in the library and reports all the
+// clips that are missing at once
+func loadArt(lib *assets.AnimationLibrary) (*Art, error) {
+	var missing []string
+	clip := func(name string) *assets.Clip {
+		c := lib.Clip(name)
+		if c == nil {
+			missing = append(missing, name)
+		}
+		return c
+	}
+	image := func(name string) *ebiten.Image {
+		if c := clip(name); c != nil {
+			return c.Image()
+		}
+		return nil
+	}
+	directional := func(prefix string) DirectionalClip {
+		return DirectionalClip{Side: clip(prefix + "/side"), Up: clip(prefix + "/up"), Down: clip(prefix + "/down")}
+	}
+
+	a := &Art{
+		TrayBackground: image("ui/tray"),
+		NoneIndicator:  image("ui/none"),
+		HealthLeft:     image("ui/health/left"),
+		HealthFill:     image("ui/health/mid"),
+		HealthRight:    image("ui/health/right"),
+
+		TowerBuild:      clip("tower/build"),
+		TowerTransition: clip("tower/transition"),
+
+		BallistaTower:      image("ballista/tower"),
+		BallistaFire:       clip("ballista/fire"),
+		BallistaProjectile: clip("ballista/projectile"),
+		BallistaImpact:     clip("ballista/impact"),
+
+		MagicTower:      image("magic/tower"),
+		MagicIdle:       clip("magic/idle"),
+		MagicAttack:     clip("magic/attack"),
+		MagicProjectile: clip("magic/projectile"),
+		MagicImpact:     clip("magic/impact"),
+
+		Firebug: CreepArt{
+			Idle:  directional("firebug/idle"),
+			Walk:  directional("firebug/walk"),
+			Death: directional("firebug/death"),
+		},
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s is missing %s", assets.AnimationManifest, strings.Join(missing, ", "))
+	}
+	return a, nil
+}
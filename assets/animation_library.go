@@ -0,0 +1,163 @@
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"maps"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AnimationManifest is the file describing every sprite sheet the game draws
+// from and the named clips cut from them
+const AnimationManifest = "animations.json"
+
+// defaultClipDuration is how long a clip takes to play when the manifest doesn't say
+const defaultClipDuration = 1.0
+
+// animationManifest is the JSON form of the manifest
+type animationManifest struct {
+	Sheets map[string]manifestSheet `json:"sheets"`
+	Clips  map[string]manifestClip  `json:"clips"`
+}
+
+// manifestSheet is one sprite sheet image
+type manifestSheet struct {
+	Image string `json:"image"` // Path of the image within the assets
+	// FrameWidth and FrameHeight are the size of the grid clips cut frames from, if any
+	FrameWidth  int `json:"frameWidth"`
+	FrameHeight int `json:"frameHeight"`
+}
+
+// manifestClip is one named clip. Its frames are either a run along a row of the
+// sheet's grid, a list of rectangles, or, with neither, the whole sheet.
+type manifestClip struct {
+	Sheet    string     `json:"sheet"`
+	Row      int        `json:"row"`      // Grid row the frames are on
+	Column   int        `json:"column"`   // Grid column of the first frame
+	Frames   int        `json:"frames"`   // Number of frames along the row
+	Rects    [][4]int   `json:"rects"`    // Frames as x, y, width, height in sheet pixels
+	Duration *float64   `json:"duration"` // Seconds to play every frame once
+	Loop     bool       `json:"loop"`
+	Pivot    [2]float64 `json:"pivot"` // See Clip.PivotX
+}
+
+// Clip is a named animation: its frames and how to play them. A still image is a
+// clip with one frame.
+type Clip struct {
+	Name     string
+	Frames   []*ebiten.Image
+	Duration float64 // Seconds to play every frame once
+	Loop     bool
+	// PivotX and PivotY are the point of a frame that is placed on the position the
+	// clip is drawn at, as fractions of the frame's width and height: 0, 0 is the
+	// top-left corner and 0.5, 0.5 the centre.
+	PivotX, PivotY float64
+}
+
+// Image returns the clip's first frame, for clips that are still images
+func (c *Clip) Image() *ebiten.Image {
+	return c.Frames[0]
+}
+
+// AnimationLibrary holds the clips from the animation manifest, keyed by name
+type AnimationLibrary struct {
+	clips map[string]*Clip
+}
+
+// Clip returns the named clip, or nil if the manifest has no such clip
+func (l *AnimationLibrary) Clip(name string) *Clip {
+	return l.clips[name]
+}
+
+// Names returns the names of every clip, sorted
+func (l *AnimationLibrary) Names() []string {
+	names := make([]string, 0, len(l.clips))
+	for name := range l.clips {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// loadAnimationLibrary reads a manifest and adds every clip's frames to the atlas.
+// The clips' frames are filled in when the atlas is built. decode loads a sheet
+// image by its path.
+func loadAnimationLibrary(data []byte, decode func(path string) (image.Image, error), atlas *AtlasBuilder) (*AnimationLibrary, error) {
+	var manifest animationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("animation manifest: %w", err)
+	}
+
+	sheets := make(map[string]image.Image)
+	lib := &AnimationLibrary{clips: make(map[string]*Clip, len(manifest.Clips))}
+	for _, name := range slices.Sorted(maps.Keys(manifest.Clips)) {
+		mc := manifest.Clips[name]
+		sheetInfo, ok := manifest.Sheets[mc.Sheet]
+		if !ok {
+			return nil, fmt.Errorf("animation manifest: clip %q uses unknown sheet %q", name, mc.Sheet)
+		}
+		sheet, ok := sheets[mc.Sheet]
+		if !ok {
+			var err error
+			if sheet, err = decode(sheetInfo.Image); err != nil {
+				return nil, fmt.Errorf("animation manifest: sheet %q: %w", mc.Sheet, err)
+			}
+			sheets[mc.Sheet] = sheet
+		}
+
+		rects, err := mc.frameRects(sheetInfo, sheet.Bounds())
+		if err != nil {
+			return nil, fmt.Errorf("animation manifest: clip %q: %w", name, err)
+		}
+		clip := &Clip{
+			Name:     name,
+			Frames:   make([]*ebiten.Image, len(rects)),
+			Duration: defaultClipDuration,
+			Loop:     mc.Loop,
+			PivotX:   mc.Pivot[0],
+			PivotY:   mc.Pivot[1],
+		}
+		if mc.Duration != nil {
+			clip.Duration = *mc.Duration
+		}
+		if clip.Duration <= 0 {
+			return nil, fmt.Errorf("animation manifest: clip %q has a duration of %g; it must be more than 0", name, clip.Duration)
+		}
+		for i, rect := range rects {
+			if !rect.In(sheet.Bounds()) {
+				return nil, fmt.Errorf("animation manifest: clip %q frame %d %v is outside its %v sheet", name, i, rect, sheet.Bounds())
+			}
+			atlas.Frame(&clip.Frames[i], sheet, rect)
+		}
+		lib.clips[name] = clip
+	}
+	return lib, nil
+}
+
+// frameRects works out the regions of the sheet the clip's frames are cut from
+func (mc manifestClip) frameRects(sheet manifestSheet, bounds image.Rectangle) ([]image.Rectangle, error) {
+	switch {
+	case len(mc.Rects) > 0:
+		rects := make([]image.Rectangle, len(mc.Rects))
+		for i, r := range mc.Rects {
+			rects[i] = image.Rect(r[0], r[1], r[0]+r[2], r[1]+r[3])
+		}
+		return rects, nil
+	case mc.Frames > 0:
+		if sheet.FrameWidth <= 0 || sheet.FrameHeight <= 0 {
+			return nil, fmt.Errorf("sheet %q needs a frameWidth and frameHeight to cut frames from", mc.Sheet)
+		}
+		rects := make([]image.Rectangle, mc.Frames)
+		for i := range rects {
+			x := (mc.Column + i) * sheet.FrameWidth
+			y := mc.Row * sheet.FrameHeight
+			rects[i] = image.Rect(x, y, x+sheet.FrameWidth, y+sheet.FrameHeight)
+		}
+		return rects, nil
+	default:
+		return []image.Rectangle{bounds}, nil
+	}
+}
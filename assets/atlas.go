@@ -0,0 +1,135 @@
+package assets
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"image/draw"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// atlasPageSize is the width and height of an atlas texture. The game's art fills
+// most of one page this size and spills a little onto a second.
+const atlasPageSize = 2048
+
+// atlasPadding is the transparent gap kept around each frame, so filtering at a
+// frame's edge doesn't pick up its neighbour
+const atlasPadding = 1
+
+// AtlasBuilder collects frames cut from sprite sheets and packs them into as few
+// shared textures as it can, so drawing a battle doesn't switch textures for
+// every tower, creep and projectile. Frames are added with a destination, which
+// Build fills in with the frame's sub-image of its atlas page.
+type AtlasBuilder struct {
+	pageSize int
+	frames   []atlasFrame
+}
+
+// atlasFrame is one frame waiting to be packed
+type atlasFrame struct {
+	sheet image.Image
+	rect  image.Rectangle // Region of the sheet
+	dst   **ebiten.Image
+	page  int
+	pos   image.Point // Top-left corner on its page
+}
+
+// NewAtlasBuilder creates a builder whose pages are pageSize pixels square
+func NewAtlasBuilder(pageSize int) *AtlasBuilder {
+	return &AtlasBuilder{pageSize: pageSize}
+}
+
+// Frame adds a region of a sheet; Build sets *dst to it
+func (b *AtlasBuilder) Frame(dst **ebiten.Image, sheet image.Image, rect image.Rectangle) {
+	b.frames = append(b.frames, atlasFrame{sheet: sheet, rect: rect, dst: dst})
+}
+
+// Image adds a whole sheet; Build sets *dst to it
+func (b *AtlasBuilder) Image(dst **ebiten.Image, sheet image.Image) {
+	b.Frame(dst, sheet, sheet.Bounds())
+}
+
+// Animation adds a row of same-sized frames from a sheet. *dst is set to the
+// frames straight away, and Build fills them in.
+func (b *AtlasBuilder) Animation(dst *[]*ebiten.Image, sheet image.Image, row, numberOfFrames, frameWidth, frameHeight int) {
+	frames := make([]*ebiten.Image, numberOfFrames)
+	for i := range frames {
+		x := i * frameWidth
+		y := row * frameHeight
+		b.Frame(&frames[i], sheet, image.Rect(x, y, x+frameWidth, y+frameHeight))
+	}
+	*dst = frames
+}
+
+// Build packs the frames onto pages, uploads the pages as textures and sets every
+// frame's destination. It returns the pages.
+func (b *AtlasBuilder) Build() ([]*ebiten.Image, error) {
+	if len(b.frames) == 0 {
+		return nil, nil
+	}
+	usedHeights, err := b.pack()
+	if err != nil {
+		return nil, err
+	}
+
+	pixels := make([]*image.RGBA, len(usedHeights))
+	for i, height := range usedHeights {
+		pixels[i] = image.NewRGBA(image.Rect(0, 0, b.pageSize, height))
+	}
+	for _, f := range b.frames {
+		dstRect := image.Rectangle{Min: f.pos, Max: f.pos.Add(f.rect.Size())}
+		draw.Draw(pixels[f.page], dstRect, f.sheet, f.rect.Min, draw.Src)
+	}
+
+	pages := make([]*ebiten.Image, len(pixels))
+	for i, p := range pixels {
+		pages[i] = ebiten.NewImageFromImage(p)
+	}
+	for _, f := range b.frames {
+		*f.dst = pages[f.page].SubImage(image.Rectangle{Min: f.pos, Max: f.pos.Add(f.rect.Size())}).(*ebiten.Image)
+	}
+	return pages, nil
+}
+
+// pack places the frames in shelves, tallest first, starting a new page when one
+// fills up. It returns how much of each page's height is used.
+func (b *AtlasBuilder) pack() ([]int, error) {
+	order := make([]*atlasFrame, len(b.frames))
+	for i := range b.frames {
+		f := &b.frames[i]
+		if !f.rect.In(f.sheet.Bounds()) {
+			return nil, fmt.Errorf("frame %v is outside its %v sheet", f.rect, f.sheet.Bounds())
+		}
+		if f.rect.Dx()+2*atlasPadding > b.pageSize || f.rect.Dy()+2*atlasPadding > b.pageSize {
+			return nil, fmt.Errorf("frame %v is too big for a %d pixel atlas page", f.rect, b.pageSize)
+		}
+		order[i] = f
+	}
+	slices.SortStableFunc(order, func(a, b *atlasFrame) int {
+		return cmp.Or(b.rect.Dy()-a.rect.Dy(), b.rect.Dx()-a.rect.Dx())
+	})
+
+	usedHeights := []int{0}
+	page, x, y, shelfHeight := 0, atlasPadding, atlasPadding, 0
+	for _, f := range order {
+		w, h := f.rect.Dx(), f.rect.Dy()
+		if x+w+atlasPadding > b.pageSize {
+			// Start a new shelf
+			x, y = atlasPadding, y+shelfHeight
+			shelfHeight = 0
+		}
+		if y+h+atlasPadding > b.pageSize {
+			// Start a new page
+			page++
+			usedHeights = append(usedHeights, 0)
+			x, y, shelfHeight = atlasPadding, atlasPadding, 0
+		}
+		f.page, f.pos = page, image.Pt(x, y)
+		x += w + atlasPadding
+		shelfHeight = max(shelfHeight, h+atlasPadding)
+		usedHeights[page] = max(usedHeights[page], y+h+atlasPadding)
+	}
+	return usedHeights, nil
+}
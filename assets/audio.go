@@ -0,0 +1,17 @@
+package assets
+
+// Sound effects and music, as the paths of WAV files within the assets. They are
+// generated by cmd/sfxgen; the game decodes them while it loads.
+const (
+	BallistaFireSound = "audio/ballista_fire.wav"
+	MagicFireSound    = "audio/magic_fire.wav"
+	ImpactSound       = "audio/impact.wav"
+	CreepDeathSound   = "audio/creep_death.wav"
+	CreepEscapeSound  = "audio/creep_escape.wav"
+	TowerBuiltSound   = "audio/tower_built.wav"
+	WaveStartSound    = "audio/wave_start.wav"
+	GameOverSound     = "audio/game_over.wav"
+
+	TitleMusic = "audio/title_music.wav"
+	GameMusic  = "audio/game_music.wav"
+)
@@ -0,0 +1,61 @@
+package assets
+
+import "fmt"
+
+// LoadStep is one piece of loading work, named for a loading screen to show
+type LoadStep struct {
+	Name string
+	Run  func() error
+}
+
+// Loader runs load steps one at a time, so a loading screen can spread them over
+// several frames and draw its progress in between. It stops at the first error.
+type Loader struct {
+	steps []LoadStep
+	next  int
+	err   error
+}
+
+// NewLoader creates a loader for the steps, run in order
+func NewLoader(steps []LoadStep) *Loader {
+	return &Loader{steps: steps}
+}
+
+// Step runs the next step and returns its error, if any
+func (l *Loader) Step() error {
+	if l.Done() {
+		return l.err
+	}
+	if err := l.steps[l.next].Run(); err != nil {
+		l.err = fmt.Errorf("%s: %w", l.steps[l.next].Name, err)
+		return l.err
+	}
+	l.next++
+	return nil
+}
+
+// Done reports whether every step has run or one has failed
+func (l *Loader) Done() bool {
+	return l.err != nil || l.next >= len(l.steps)
+}
+
+// Progress returns the fraction of the steps that have run, from 0 to 1
+func (l *Loader) Progress() float64 {
+	if len(l.steps) == 0 {
+		return 1
+	}
+	return float64(l.next) / float64(len(l.steps))
+}
+
+// Current returns the name of the step that runs next, or "" when done
+func (l *Loader) Current() string {
+	if l.next >= len(l.steps) {
+		return ""
+	}
+	return l.steps[l.next].Name
+}
+
+// Err returns the error that stopped the loader, if any
+func (l *Loader) Err() error {
+	return l.err
+}
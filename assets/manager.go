@@ -0,0 +1,143 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/png" // Decoder for the sprite sheets and tilesets
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AssetManager loads the game's files when they are first asked for and keeps
+// them. A file in the override directory takes the place of the embedded file
+// with the same path, so artists and modders can swap any asset without a
+// rebuild. Nothing is loaded until it is asked for and failures are returned as
+// errors, so the package can be used without a graphics context.
+type AssetManager struct {
+	overrideDir string                   // Searched before the embedded files; "" for none
+	decoded     map[string]image.Image   // Decoded images, by path
+	images      map[string]*ebiten.Image // Images uploaded as textures, by path
+	animations  *AnimationLibrary        // Built on first use
+}
+
+// NewAssetManager creates a manager that looks in overrideDir, if it isn't empty,
+// before the embedded assets
+func NewAssetManager(overrideDir string) *AssetManager {
+	m := &AssetManager{overrideDir: overrideDir}
+	m.Reload()
+	return m
+}
+
+// Reload forgets everything loaded so far, so the next request reads the files
+// again and picks up changes in the override directory
+func (m *AssetManager) Reload() {
+	m.decoded = make(map[string]image.Image)
+	m.images = make(map[string]*ebiten.Image)
+	m.animations = nil
+}
+
+// ReadFile reads a file by its slash-separated path within the assets, from the
+// override directory if it has it
+func (m *AssetManager) ReadFile(name string) ([]byte, error) {
+	if m.overrideDir != "" {
+		data, err := os.ReadFile(filepath.Join(m.overrideDir, filepath.FromSlash(name)))
+		if err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	return assets.ReadFile(name)
+}
+
+// Image returns an image as a texture of its own
+func (m *AssetManager) Image(name string) (*ebiten.Image, error) {
+	if img, ok := m.images[name]; ok {
+		return img, nil
+	}
+	decoded, err := m.decode(name)
+	if err != nil {
+		return nil, err
+	}
+	img := ebiten.NewImageFromImage(decoded)
+	m.images[name] = img
+	delete(m.decoded, name)
+	return img, nil
+}
+
+// decode returns an image decoded but not yet uploaded
+func (m *AssetManager) decode(name string) (image.Image, error) {
+	if img, ok := m.decoded[name]; ok {
+		return img, nil
+	}
+	data, err := m.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", name, err)
+	}
+	m.decoded[name] = img
+	return img, nil
+}
+
+// Animations returns the clips described by the animation manifest. The first
+// call decodes the sheets and packs the frames into atlas textures.
+func (m *AssetManager) Animations() (*AnimationLibrary, error) {
+	if m.animations != nil {
+		return m.animations, nil
+	}
+	manifest, err := m.ReadFile(AnimationManifest)
+	if err != nil {
+		return nil, err
+	}
+	atlas := NewAtlasBuilder(atlasPageSize)
+	lib, err := loadAnimationLibrary(manifest, m.decode, atlas)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := atlas.Build(); err != nil {
+		return nil, err
+	}
+
+	// The sheets are in the atlas now, so their decoded pixels aren't needed
+	clear(m.decoded)
+	m.animations = lib
+	return lib, nil
+}
+
+// AnimationSteps returns the steps that load the animation library, one for each
+// sprite sheet and one to pack them, for a loading screen to show progress through
+func (m *AssetManager) AnimationSteps() []LoadStep {
+	fail := func(err error) []LoadStep {
+		return []LoadStep{{Name: AnimationManifest, Run: func() error { return err }}}
+	}
+	data, err := m.ReadFile(AnimationManifest)
+	if err != nil {
+		return fail(err)
+	}
+	var manifest animationManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fail(fmt.Errorf("animation manifest: %w", err))
+	}
+
+	var steps []LoadStep
+	for _, name := range slices.Sorted(maps.Keys(manifest.Sheets)) {
+		path := manifest.Sheets[name].Image
+		steps = append(steps, LoadStep{Name: path, Run: func() error {
+			_, err := m.decode(path)
+			return err
+		}})
+	}
+	return append(steps, LoadStep{Name: "Packing sprites", Run: func() error {
+		_, err := m.Animations()
+		return err
+	}})
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"towerDefense/assets"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+const (
+	audioSampleRate = 44100
+	musicFadeTime   = 1.5 // Seconds a music crossfade takes
+)
+
+// Sound is a sound effect
+type Sound int
+
+const (
+	SoundNone Sound = iota
+	SoundBallistaFire
+	SoundMagicFire
+	SoundImpact
+	SoundCreepDeath
+	SoundCreepEscape
+	SoundTowerBuilt
+	SoundWaveStart
+	SoundGameOver
+	soundCount
+)
+
+// Music is a looping music track
+type Music int
+
+const (
+	MusicNone Music = iota
+	MusicTitle
+	MusicGame
+	musicCount
+)
+
+// soundSpec says how a sound effect plays. Voices caps how many copies can play
+// at once and MinGap how soon it can start again, so a burst of identical
+// events (twenty impacts in one tick) plays a few times rather than stacking
+// into a clipped roar.
+type soundSpec struct {
+	Path   string  // WAV file within the assets
+	Volume float64 // Relative to the effects volume
+	Voices int
+	MinGap float64 // Seconds
+}
+
+var soundSpecs = map[Sound]soundSpec{
+	SoundBallistaFire: {Path: assets.BallistaFireSound, Volume: 0.5, Voices: 4, MinGap: 0.05},
+	SoundMagicFire:    {Path: assets.MagicFireSound, Volume: 0.4, Voices: 4, MinGap: 0.05},
+	SoundImpact:       {Path: assets.ImpactSound, Volume: 0.4, Voices: 4, MinGap: 0.04},
+	SoundCreepDeath:   {Path: assets.CreepDeathSound, Volume: 0.5, Voices: 3, MinGap: 0.08},
+	SoundCreepEscape:  {Path: assets.CreepEscapeSound, Volume: 0.7, Voices: 2, MinGap: 0.2},
+	SoundTowerBuilt:   {Path: assets.TowerBuiltSound, Volume: 0.6, Voices: 2, MinGap: 0.1},
+	SoundWaveStart:    {Path: assets.WaveStartSound, Volume: 0.7, Voices: 1, MinGap: 1},
+	SoundGameOver:     {Path: assets.GameOverSound, Volume: 0.8, Voices: 1, MinGap: 1},
+}
+
+// musicPaths are the WAV files of the music tracks within the assets
+var musicPaths = map[Music]string{
+	MusicTitle: assets.TitleMusic,
+	MusicGame:  assets.GameMusic,
+}
+
+// soundBank holds the decoded sound and the players that play it
+type soundBank struct {
+	spec     soundSpec
+	pcm      []byte
+	players  []*audio.Player // Created as needed, up to spec.Voices
+	cooldown float64         // Seconds until the sound may start again
+}
+
+// musicTrack is a looping track and how loud it currently is in a crossfade
+type musicTrack struct {
+	player *audio.Player
+	level  float64 // 0 to 1, before the volume settings
+}
+
+// AudioManager plays sound effects and music at the volumes in the Config.
+// Sounds are decoded once while the game loads; music crossfades when the track
+// changes. Sounds that haven't loaded yet are silent.
+type AudioManager struct {
+	context *audio.Context
+	config  *Config
+	sounds  [soundCount]*soundBank
+	music   [musicCount]*musicTrack
+	current Music
+}
+
+// NewAudioManager creates the audio context. There can only be one, as ebiten
+// allows a single audio context. LoadSteps decodes the sounds and tracks.
+func NewAudioManager(config *Config) *AudioManager {
+	return &AudioManager{
+		context: audio.NewContext(audioSampleRate),
+		config:  config,
+	}
+}
+
+// LoadSteps returns a step decoding each sound and track, for the loading screen
+func (am *AudioManager) LoadSteps() []assets.LoadStep {
+	var steps []assets.LoadStep
+	for sound := SoundNone + 1; sound < soundCount; sound++ {
+		spec, ok := soundSpecs[sound]
+		if !ok {
+			continue
+		}
+		steps = append(steps, assets.LoadStep{Name: spec.Path, Run: func() error { return am.loadSound(sound, spec) }})
+	}
+	for music := MusicNone + 1; music < musicCount; music++ {
+		path, ok := musicPaths[music]
+		if !ok {
+			continue
+		}
+		steps = append(steps, assets.LoadStep{Name: path, Run: func() error { return am.loadMusic(music, path) }})
+	}
+	return steps
+}
+
+// loadSound decodes a sound effect into memory
+func (am *AudioManager) loadSound(sound Sound, spec soundSpec) error {
+	stream, err := decodeWAV(spec.Path)
+	if err != nil {
+		return err
+	}
+	pcm, err := io.ReadAll(stream)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", spec.Path, err)
+	}
+	am.sounds[sound] = &soundBank{spec: spec, pcm: pcm}
+	return nil
+}
+
+// loadMusic sets up a music track to loop
+func (am *AudioManager) loadMusic(music Music, path string) error {
+	stream, err := decodeWAV(path)
+	if err != nil {
+		return err
+	}
+	player, err := am.context.NewPlayer(audio.NewInfiniteLoop(stream, stream.Length()))
+	if err != nil {
+		return err
+	}
+	am.music[music] = &musicTrack{player: player}
+	return nil
+}
+
+// decodeWAV decodes a WAV file from the assets at the game's sample rate
+func decodeWAV(path string) (*wav.Stream, error) {
+	data, err := gameAssets.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := wav.DecodeWithSampleRate(audioSampleRate, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return stream, nil
+}
+
+// Subscribe plays the sound effects for gameplay events
+func (am *AudioManager) Subscribe(events *EventBus) {
+	events.TowerFired.Subscribe(func(e TowerFired) { am.Play(towerDefinitions[e.TowerID].FireSound) })
+	events.ProjectileHit.Subscribe(func(ProjectileHit) { am.Play(SoundImpact) })
+	events.CreepKilled.Subscribe(func(CreepKilled) { am.Play(SoundCreepDeath) })
+	events.CreepEscaped.Subscribe(func(CreepEscaped) { am.Play(SoundCreepEscape) })
+	events.TowerBuilt.Subscribe(func(TowerBuilt) { am.Play(SoundTowerBuilt) })
+	events.WaveStarted.Subscribe(func(WaveStarted) { am.Play(SoundWaveStart) })
+}
+
+// Play starts a sound effect, unless it is already playing on all its voices
+// or started too recently
+func (am *AudioManager) Play(sound Sound) {
+	if am == nil || sound <= SoundNone || sound >= soundCount {
+		return
+	}
+	bank := am.sounds[sound]
+	if bank == nil || bank.cooldown > 0 {
+		return
+	}
+
+	var player *audio.Player
+	for _, p := range bank.players {
+		if !p.IsPlaying() {
+			player = p
+			break
+		}
+	}
+	if player == nil {
+		if len(bank.players) >= bank.spec.Voices {
+			return // Every voice is busy
+		}
+		player = am.context.NewPlayerFromBytes(bank.pcm)
+		bank.players = append(bank.players, player)
+	}
+
+	if err := player.Rewind(); err != nil {
+		return
+	}
+	player.SetVolume(bank.spec.Volume * am.config.EffectsVolume * am.config.MasterVolume)
+	player.Play()
+	bank.cooldown = bank.spec.MinGap
+}
+
+// PlayMusic crossfades to a track; MusicNone fades the music out
+func (am *AudioManager) PlayMusic(music Music) {
+	if am == nil || music == am.current {
+		return
+	}
+	am.current = music
+	if track := am.music[music]; track != nil && !track.player.IsPlaying() {
+		track.player.SetVolume(0)
+		track.player.Play()
+	}
+}
+
+// Update advances cooldowns and crossfades and applies volume changes
+func (am *AudioManager) Update(deltaTime float64) {
+	if am == nil {
+		return
+	}
+	for _, bank := range am.sounds {
+		if bank != nil && bank.cooldown > 0 {
+			bank.cooldown -= deltaTime
+		}
+	}
+
+	musicVolume := am.config.MusicVolume * am.config.MasterVolume
+	step := deltaTime / musicFadeTime
+	for music, track := range am.music {
+		if track == nil {
+			continue
+		}
+		if Music(music) == am.current {
+			track.level = min(track.level+step, 1)
+		} else {
+			track.level = max(track.level-step, 0)
+		}
+
+		if track.level == 0 {
+			if track.player.IsPlaying() {
+				track.player.Pause() // Resumes from here if it fades back in
+			}
+			continue
+		}
+		track.player.SetVolume(track.level * musicVolume)
+	}
+}
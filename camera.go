@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Camera tuning
+const (
+	cameraMaxZoom        = 2.0   // Closest zoom, in virtual pixels per world pixel
+	cameraMinDefaultZoom = 0.5   // Big maps start at least this close instead of shrinking to fit
+	cameraZoomStep       = 1.1   // Zoom factor per mouse wheel notch
+	cameraPanSpeed       = 900.0 // Keyboard and edge panning speed in virtual pixels per second
+	cameraEdgeSize       = 12.0  // How close to the window edge the cursor must be to pan, in virtual pixels
+	cameraFollowSpeed    = 10.0  // How quickly the view catches up with its target (per second)
+)
+
+// Camera is a view onto the map: the world point at the centre of the map
+// viewport plus a zoom. Panning moves a target the view eases towards, so
+// keyboard, edge and Follow movement all glide; dragging and wheel zoom move
+// the view directly so the world stays pinned under the cursor.
+type Camera struct {
+	X, Y             float64 // World pixel at the centre of the viewport
+	Zoom             float64 // Virtual pixels per world pixel, so the view holds across screen sizes
+	targetX, targetY float64 // Where the view is easing towards
+
+	initialized          bool
+	dragging             bool
+	dragLastX, dragLastY int
+	keys                 KeyBindings // Pan and reset keys; the arrow keys always pan too
+}
+
+// NewCamera creates a camera that centres itself on the map on its first update
+func NewCamera() *Camera {
+	return &Camera{keys: defaultKeyBindings()}
+}
+
+// SetKeyBindings sets the keys that pan and reset the view
+func (c *Camera) SetKeyBindings(keys KeyBindings) {
+	c.keys = keys
+}
+
+// Reset centres the view on the map at the default zoom
+func (c *Camera) Reset(vp MapViewport) {
+	c.Zoom = math.Max(c.fitZoom(vp), math.Min(cameraMinDefaultZoom, cameraMaxZoom))
+	c.X, c.Y = vp.MapWidth/2, vp.MapHeight/2
+	c.targetX, c.targetY = c.X, c.Y
+	c.initialized = true
+	c.clamp(vp)
+}
+
+// Follow makes the view glide towards a world pixel position, such as the
+// inspected tower when the focus key is pressed
+func (c *Camera) Follow(worldX, worldY float64) {
+	c.targetX, c.targetY = worldX, worldY
+}
+
+// Transform returns the world-to-screen scale and offset for the current view
+func (c *Camera) Transform(vp MapViewport) (scale, offsetX, offsetY float64) {
+	if !c.initialized {
+		c.Reset(vp)
+	}
+	scale = c.scale(vp)
+	return scale, vp.Width/2 - c.X*scale, vp.Height/2 - c.Y*scale
+}
+
+// scale returns the zoom in screen pixels per world pixel
+func (c *Camera) scale(vp MapViewport) float64 {
+	return c.Zoom * vp.ScreenScale
+}
+
+// fitZoom returns the zoom at which the whole map fits the viewport
+func (c *Camera) fitZoom(vp MapViewport) float64 {
+	return vp.FitScale / vp.ScreenScale
+}
+
+// Update applies zoom and pan input and eases the view towards its target
+func (c *Camera) Update(deltaTime float64, vp MapViewport) {
+	if !c.initialized {
+		c.Reset(vp)
+	}
+
+	mouseX, mouseY := ebiten.CursorPosition()
+	overMap := float64(mouseX) < vp.TrayX
+
+	// The reset key (Home by default) snaps back to the starting view
+	if c.keys.Pressed(ActionResetCamera) {
+		c.Reset(vp)
+		return
+	}
+
+	// Mouse wheel zooms around the cursor
+	if _, wheelY := ebiten.Wheel(); wheelY != 0 && overMap {
+		c.zoomAt(math.Pow(cameraZoomStep, wheelY), float64(mouseX), float64(mouseY), vp)
+	}
+
+	// Middle mouse drags the map
+	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle) {
+		if c.dragging {
+			dx := float64(mouseX-c.dragLastX) / c.scale(vp)
+			dy := float64(mouseY-c.dragLastY) / c.scale(vp)
+			c.X -= dx
+			c.Y -= dy
+			c.targetX, c.targetY = c.X, c.Y
+		}
+		c.dragging = overMap || c.dragging
+		c.dragLastX, c.dragLastY = mouseX, mouseY
+	} else {
+		c.dragging = false
+	}
+
+	// Keyboard and screen-edge panning move the target
+	panX, panY := 0.0, 0.0
+	if ebiten.IsKeyPressed(ebiten.KeyArrowLeft) || c.keys.Pressed(ActionPanLeft) {
+		panX--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowRight) || c.keys.Pressed(ActionPanRight) {
+		panX++
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowUp) || c.keys.Pressed(ActionPanUp) {
+		panY--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyArrowDown) || c.keys.Pressed(ActionPanDown) {
+		panY++
+	}
+	if ebiten.IsFocused() && !c.dragging {
+		edge := cameraEdgeSize * vp.ScreenScale
+		if mouseX >= 0 && float64(mouseX) < edge {
+			panX--
+		}
+		if mouseX < vp.ScreenWidth && float64(mouseX) >= float64(vp.ScreenWidth)-edge {
+			panX++
+		}
+		if mouseY >= 0 && float64(mouseY) < edge {
+			panY--
+		}
+		if mouseY < vp.ScreenHeight && float64(mouseY) >= float64(vp.ScreenHeight)-edge {
+			panY++
+		}
+	}
+	if panX != 0 || panY != 0 {
+		step := cameraPanSpeed * deltaTime / c.Zoom
+		c.targetX += panX * step
+		c.targetY += panY * step
+	}
+
+	// Ease towards the target
+	t := math.Min(1, cameraFollowSpeed*deltaTime)
+	c.X += (c.targetX - c.X) * t
+	c.Y += (c.targetY - c.Y) * t
+
+	c.clamp(vp)
+}
+
+// zoomAt multiplies the zoom while keeping the world point under the given screen position fixed
+func (c *Camera) zoomAt(factor, screenX, screenY float64, vp MapViewport) {
+	scale, offsetX, offsetY := c.Transform(vp)
+	worldX := (screenX - offsetX) / scale
+	worldY := (screenY - offsetY) / scale
+
+	fit := c.fitZoom(vp)
+	c.Zoom = math.Max(fit, math.Min(c.Zoom*factor, math.Max(cameraMaxZoom, fit)))
+
+	scale = c.scale(vp)
+	c.X = worldX - (screenX-vp.Width/2)/scale
+	c.Y = worldY - (screenY-vp.Height/2)/scale
+	c.targetX, c.targetY = c.X, c.Y
+	c.clamp(vp)
+}
+
+// clamp keeps the map covering the viewport; a map smaller than the viewport stays centred
+func (c *Camera) clamp(vp MapViewport) {
+	scale := c.scale(vp)
+	c.X = clampAxis(c.X, vp.Width/scale, vp.MapWidth)
+	c.Y = clampAxis(c.Y, vp.Height/scale, vp.MapHeight)
+	c.targetX = clampAxis(c.targetX, vp.Width/scale, vp.MapWidth)
+	c.targetY = clampAxis(c.targetY, vp.Height/scale, vp.MapHeight)
+}
+
+// clampAxis clamps a view centre on one axis given the visible and total world extent
+func clampAxis(center, visible, total float64) float64 {
+	if visible >= total {
+		return total / 2
+	}
+	return math.Max(visible/2, math.Min(center, total-visible/2))
+}
@@ -0,0 +1,71 @@
+// Command levelcheck validates Tiled maps for the game.
+//
+// Usage:
+//
+//	levelcheck map.tmj [more.tmj ...]
+//
+// It loads each map and its tilesets from disk and reports unknown tile IDs,
+// missing tilesets, layer size mismatches, bad or misplaced waypoints,
+// unreachable exits and maps without buildable tiles. The exit status is 1
+// if any map has a problem, so it can run in CI.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"towerDefense/tiled"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: levelcheck map.tmj [more.tmj ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	failed := false
+	for _, mapPath := range flag.Args() {
+		errs := checkMap(mapPath)
+		if len(errs) == 0 {
+			fmt.Printf("%s: ok\n", mapPath)
+			continue
+		}
+		failed = true
+		for _, err := range errs {
+			fmt.Printf("%s: %v\n", mapPath, err)
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// checkMap loads one map and returns every problem found in it
+func checkMap(mapPath string) []error {
+	contents, err := os.ReadFile(mapPath)
+	if err != nil {
+		return []error{err}
+	}
+
+	level, err := tiled.Decode(contents)
+	if err != nil {
+		return []error{fmt.Errorf("invalid map JSON: %w", err)}
+	}
+
+	// Missing tilesets are reported, but we carry on so the rest of the map still gets checked
+	var errs []error
+	if err := level.LoadTilesets(os.ReadFile, path.Dir(filepath.ToSlash(mapPath))); err != nil {
+		errs = append(errs, err)
+	}
+	level.BuildTileFlags()
+
+	return append(errs, level.Validate()...)
+}
@@ -0,0 +1,270 @@
+// Command sfxgen synthesises the game's sound effects and music loops.
+//
+// Usage:
+//
+//	sfxgen [-out assets/audio]
+//
+// Every sound is built from simple oscillators and noise, so the output is the
+// same on every run. The files are 16-bit mono WAV and are embedded by the
+// assets package; run this again after changing a recipe and commit the result.
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+const sampleRate = 22050 // The game resamples on load, so this only sets the quality
+
+// wave is a mono signal with samples in [-1, 1]
+type wave []float64
+
+// recipes maps each output file to the function that synthesises it
+var recipes = map[string]func() wave{
+	"ballista_fire.wav": ballistaFire,
+	"magic_fire.wav":    magicFire,
+	"impact.wav":        impact,
+	"creep_death.wav":   creepDeath,
+	"creep_escape.wav":  creepEscape,
+	"tower_built.wav":   towerBuilt,
+	"wave_start.wav":    waveStart,
+	"game_over.wav":     gameOver,
+	"title_music.wav":   titleMusic,
+	"game_music.wav":    gameMusic,
+}
+
+func main() {
+	out := flag.String("out", "assets/audio", "directory to write the WAV files to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for name, recipe := range recipes {
+		path := filepath.Join(*out, name)
+		if err := writeWAV(path, recipe()); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %s\n", path)
+	}
+}
+
+// samples returns how many samples last the given number of seconds
+func samples(seconds float64) int {
+	return int(seconds * sampleRate)
+}
+
+// tone is an oscillator sliding from one frequency to another, shaped by a
+// linear attack and an exponential decay. shape maps the phase (in cycles) to a sample.
+func tone(seconds, fromHz, toHz, attack, decay float64, shape func(phase float64) float64) wave {
+	w := make(wave, samples(seconds))
+	phase := 0.0
+	for i := range w {
+		t := float64(i) / sampleRate
+		progress := t / seconds
+		phase += (fromHz + (toHz-fromHz)*progress) / sampleRate
+		w[i] = shape(phase) * envelope(t, attack, decay)
+	}
+	return w
+}
+
+// noise is white noise from a fixed seed with the same envelope as tone
+func noise(seconds, attack, decay float64, seed int64) wave {
+	rng := rand.New(rand.NewSource(seed))
+	w := make(wave, samples(seconds))
+	for i := range w {
+		w[i] = (rng.Float64()*2 - 1) * envelope(float64(i)/sampleRate, attack, decay)
+	}
+	return w
+}
+
+// envelope rises linearly over attack seconds, then decays with time constant decay
+func envelope(t, attack, decay float64) float64 {
+	if t < attack {
+		return t / attack
+	}
+	return math.Exp(-(t - attack) / decay)
+}
+
+func sine(phase float64) float64 { return math.Sin(2 * math.Pi * phase) }
+
+func square(phase float64) float64 {
+	if math.Mod(phase, 1) < 0.5 {
+		return 1
+	}
+	return -1
+}
+
+func triangle(phase float64) float64 {
+	p := math.Mod(phase, 1)
+	return 4*math.Abs(p-0.5) - 1
+}
+
+func saw(phase float64) float64 { return 2*math.Mod(phase, 1) - 1 }
+
+// note returns the frequency of a MIDI note number
+func note(n int) float64 {
+	return 440 * math.Pow(2, float64(n-69)/12)
+}
+
+// mix adds src into dst starting at the given time, scaled by gain, growing dst if needed
+func mix(dst wave, src wave, at, gain float64) wave {
+	start := samples(at)
+	if need := start + len(src); need > len(dst) {
+		dst = append(dst, make(wave, need-len(dst))...)
+	}
+	for i, s := range src {
+		dst[start+i] += s * gain
+	}
+	return dst
+}
+
+// lowPass smooths a wave with a one-pole filter; smaller alpha is darker
+func lowPass(w wave, alpha float64) wave {
+	prev := 0.0
+	for i, s := range w {
+		prev += alpha * (s - prev)
+		w[i] = prev
+	}
+	return w
+}
+
+func ballistaFire() wave {
+	w := tone(0.25, 320, 90, 0.002, 0.06, triangle)
+	return mix(w, lowPass(noise(0.12, 0.001, 0.03, 1), 0.3), 0, 0.5)
+}
+
+func magicFire() wave {
+	w := tone(0.4, 500, 1400, 0.02, 0.12, sine)
+	return mix(w, tone(0.4, 750, 2100, 0.02, 0.1, sine), 0, 0.4)
+}
+
+func impact() wave {
+	w := lowPass(noise(0.2, 0.001, 0.04, 2), 0.25)
+	return mix(w, tone(0.15, 140, 60, 0.001, 0.04, sine), 0, 0.8)
+}
+
+func creepDeath() wave {
+	w := tone(0.45, 600, 120, 0.005, 0.15, square)
+	return lowPass(w, 0.2)
+}
+
+func creepEscape() wave {
+	var w wave
+	for i := 0; i < 2; i++ {
+		w = mix(w, lowPass(tone(0.18, 180, 160, 0.005, 0.1, saw), 0.3), float64(i)*0.2, 1)
+	}
+	return w
+}
+
+func towerBuilt() wave {
+	var w wave
+	w = mix(w, tone(0.3, note(72), note(72), 0.005, 0.12, triangle), 0, 1)
+	return mix(w, tone(0.5, note(79), note(79), 0.005, 0.18, triangle), 0.12, 1)
+}
+
+func waveStart() wave {
+	var w wave
+	for i, n := range []int{60, 64, 67} {
+		w = mix(w, lowPass(tone(0.35, note(n), note(n), 0.03, 0.2, saw), 0.15), float64(i)*0.15, 0.8)
+	}
+	return w
+}
+
+func gameOver() wave {
+	var w wave
+	for i, n := range []int{67, 63, 60, 55} {
+		w = mix(w, lowPass(tone(0.6, note(n), note(n), 0.01, 0.3, square), 0.1), float64(i)*0.3, 0.7)
+	}
+	return w
+}
+
+// titleMusic is a slow, calm arpeggio over two chords; it loops every 8 seconds
+func titleMusic() wave {
+	w := make(wave, samples(8))
+	chords := [][]int{{57, 60, 64, 69}, {53, 57, 60, 65}}
+	for bar, chord := range chords {
+		for step := 0; step < 8; step++ {
+			n := chord[step%len(chord)]
+			at := float64(bar)*4 + float64(step)*0.5
+			w = mix(w, tone(0.9, note(n), note(n), 0.02, 0.35, triangle), at, 0.35)
+		}
+		w = mix(w, tone(4, note(chord[0]-12), note(chord[0]-12), 0.3, 2, sine), float64(bar)*4, 0.4)
+	}
+	return loopable(w, samples(8))
+}
+
+// gameMusic is a driving bass line and lead over four bars; it loops every 8 seconds
+func gameMusic() wave {
+	w := make(wave, samples(8))
+	bass := []int{45, 45, 48, 43}
+	lead := []int{69, 72, 76, 72, 69, 67, 64, 67}
+	for bar, root := range bass {
+		for step := 0; step < 8; step++ {
+			at := float64(bar)*2 + float64(step)*0.25
+			w = mix(w, lowPass(tone(0.22, note(root), note(root), 0.005, 0.1, saw), 0.12), at, 0.5)
+			n := lead[(bar*2+step)%len(lead)]
+			if step%2 == 0 {
+				w = mix(w, tone(0.3, note(n), note(n), 0.01, 0.12, square), at, 0.12)
+			}
+		}
+		w = mix(w, lowPass(noise(0.08, 0.001, 0.02, int64(bar)), 0.5), float64(bar)*2+1, 0.3)
+	}
+	return loopable(w, samples(8))
+}
+
+// loopable folds anything past length back onto the start, so notes ringing past
+// the loop point carry on into the next loop instead of being cut off
+func loopable(w wave, length int) wave {
+	for i := length; i < len(w); i++ {
+		w[i%length] += w[i]
+	}
+	return w[:length]
+}
+
+// writeWAV normalises a wave and writes it as a 16-bit mono WAV file
+func writeWAV(path string, w wave) error {
+	peak := 0.0
+	for _, s := range w {
+		peak = math.Max(peak, math.Abs(s))
+	}
+	gain := 1.0
+	if peak > 0 {
+		gain = 0.9 / peak // Leave some headroom for the game's own mixing
+	}
+
+	data := make([]byte, 2*len(w))
+	for i, s := range w {
+		binary.LittleEndian.PutUint16(data[2*i:], uint16(int16(s*gain*math.MaxInt16)))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	const channels, bitsPerSample = 1, 16
+	header := []any{
+		[]byte("RIFF"), uint32(36 + len(data)), []byte("WAVE"),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(channels), uint32(sampleRate),
+		uint32(sampleRate * channels * bitsPerSample / 8), uint16(channels * bitsPerSample / 8), uint16(bitsPerSample),
+		[]byte("data"), uint32(len(data)),
+	}
+	for _, field := range header {
+		if err := binary.Write(f, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	return f.Close()
+}
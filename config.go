@@ -0,0 +1,208 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// configFileName is the settings file inside appDataDir
+const configFileName = "config.json"
+
+// Action is something the player can bind a key to
+type Action string
+
+const (
+	ActionPause          Action = "pause"
+	ActionCycleSpeed     Action = "cycleSpeed"
+	ActionCycleTargeting Action = "cycleTargeting"
+	ActionResetCamera    Action = "resetCamera"
+	ActionFocusTower     Action = "focusTower"
+	ActionPanUp          Action = "panUp"
+	ActionPanDown        Action = "panDown"
+	ActionPanLeft        Action = "panLeft"
+	ActionPanRight       Action = "panRight"
+	ActionDebugOverlay   Action = "debugOverlay"
+)
+
+// actionOrder lists the bindable actions in the order the settings screen shows them
+var actionOrder = []Action{
+	ActionPause, ActionCycleSpeed, ActionCycleTargeting, ActionResetCamera, ActionFocusTower,
+	ActionPanUp, ActionPanDown, ActionPanLeft, ActionPanRight, ActionDebugOverlay,
+}
+
+// actionNames are the labels the settings screen uses for each action
+var actionNames = map[Action]string{
+	ActionPause:          "Pause",
+	ActionCycleSpeed:     "Game speed",
+	ActionCycleTargeting: "Targeting",
+	ActionResetCamera:    "Reset camera",
+	ActionFocusTower:     "Centre on tower",
+	ActionPanUp:          "Pan up",
+	ActionPanDown:        "Pan down",
+	ActionPanLeft:        "Pan left",
+	ActionPanRight:       "Pan right",
+	ActionDebugOverlay:   "Debug overlay",
+}
+
+// KeyBindings maps each action to its key. In the config file the keys are
+// stored by name, e.g. "pause": "P".
+type KeyBindings map[Action]ebiten.Key
+
+// defaultKeyBindings returns the bindings used when there is no config file
+func defaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		ActionPause:          ebiten.KeyP,
+		ActionCycleSpeed:     ebiten.KeyF,
+		ActionCycleTargeting: ebiten.KeyT,
+		ActionResetCamera:    ebiten.KeyHome,
+		ActionFocusTower:     ebiten.KeyC,
+		ActionPanUp:          ebiten.KeyW,
+		ActionPanDown:        ebiten.KeyS,
+		ActionPanLeft:        ebiten.KeyA,
+		ActionPanRight:       ebiten.KeyD,
+		ActionDebugOverlay:   ebiten.KeyF3,
+	}
+}
+
+// Pressed reports whether the key bound to an action is held down
+func (kb KeyBindings) Pressed(action Action) bool {
+	key, ok := kb[action]
+	return ok && ebiten.IsKeyPressed(key)
+}
+
+// JustPressed reports whether the key bound to an action went down this tick
+func (kb KeyBindings) JustPressed(action Action) bool {
+	key, ok := kb[action]
+	return ok && inpututil.IsKeyJustPressed(key)
+}
+
+// Bind binds a key to an action. An action that already had the key gets the
+// action's old key instead, so no key ever does two things.
+func (kb KeyBindings) Bind(action Action, key ebiten.Key) {
+	old := kb[action]
+	for other, otherKey := range kb {
+		if other != action && otherKey == key {
+			kb[other] = old
+		}
+	}
+	kb[action] = key
+}
+
+// Config holds the player's settings. It is saved as JSON in the user's config directory.
+type Config struct {
+	WindowWidth      int         `json:"windowWidth"`
+	WindowHeight     int         `json:"windowHeight"`
+	Fullscreen       bool        `json:"fullscreen"`
+	VSync            bool        `json:"vsync"`
+	ScreenMode       ScreenMode  `json:"screenMode"`   // How the virtual screen is fitted to the window
+	HighDPI          bool        `json:"highDPI"`      // Draw at the monitor's full pixel density
+	UIScale          float64     `json:"uiScale"`      // Multiplies the size of the HUD, tray and menus
+	DefaultSpeed     int         `json:"defaultSpeed"` // Game speed a new game starts at, one of gameSpeeds
+	ShowHealthBars   bool        `json:"showHealthBars"`
+	SceneTransitions bool        `json:"sceneTransitions"` // Fade and slide between scenes
+	Keys             KeyBindings `json:"keys"`
+	MasterVolume     float64     `json:"masterVolume"` // 0 to 1
+	MusicVolume      float64     `json:"musicVolume"`  // 0 to 1
+	EffectsVolume    float64     `json:"effectsVolume"`
+}
+
+// DefaultConfig returns the settings used when there is no config file
+func DefaultConfig() *Config {
+	return &Config{
+		WindowWidth:      screenWidth,
+		WindowHeight:     screenHeight,
+		VSync:            true,
+		ScreenMode:       ScreenFit,
+		HighDPI:          true,
+		UIScale:          1,
+		DefaultSpeed:     gameSpeeds[0],
+		ShowHealthBars:   true,
+		SceneTransitions: true,
+		Keys:             defaultKeyBindings(),
+		MasterVolume:     1,
+		MusicVolume:      0.7,
+		EffectsVolume:    0.8,
+	}
+}
+
+// LoadConfig reads the config file. A missing file gives the defaults; a broken
+// one gives the defaults and an error. Missing or invalid values are defaulted,
+// so a config from an older version still loads.
+func LoadConfig() (*Config, error) {
+	config := DefaultConfig()
+	dir, err := appDataDir()
+	if err != nil {
+		return config, fmt.Errorf("finding config directory: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, configFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return DefaultConfig(), fmt.Errorf("reading config: %w", err)
+	}
+	config.sanitize()
+	return config, nil
+}
+
+// sanitize replaces out-of-range values with their defaults
+func (c *Config) sanitize() {
+	defaults := DefaultConfig()
+	if c.WindowWidth <= 0 || c.WindowHeight <= 0 {
+		c.WindowWidth, c.WindowHeight = defaults.WindowWidth, defaults.WindowHeight
+	}
+	if !slices.Contains(screenModeOrder, c.ScreenMode) {
+		c.ScreenMode = defaults.ScreenMode
+	}
+	if c.UIScale < uiScaleOptions[0] || c.UIScale > uiScaleOptions[len(uiScaleOptions)-1] {
+		c.UIScale = defaults.UIScale
+	}
+	validSpeed := false
+	for _, speed := range gameSpeeds {
+		validSpeed = validSpeed || speed == c.DefaultSpeed
+	}
+	if !validSpeed {
+		c.DefaultSpeed = defaults.DefaultSpeed
+	}
+	if c.Keys == nil {
+		c.Keys = KeyBindings{}
+	}
+	for action, key := range defaults.Keys {
+		if _, ok := c.Keys[action]; !ok {
+			c.Keys[action] = key
+		}
+	}
+	c.MasterVolume = min(max(c.MasterVolume, 0), 1)
+	c.MusicVolume = min(max(c.MusicVolume, 0), 1)
+	c.EffectsVolume = min(max(c.EffectsVolume, 0), 1)
+}
+
+// Save writes the config file
+func (c *Config) Save() error {
+	dir, err := appDataDir()
+	if err != nil {
+		return fmt.Errorf("finding config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, configFileName), data, 0o644)
+}
+
+// ApplyDisplay applies the window size, fullscreen and vsync settings
+func (c *Config) ApplyDisplay() {
+	ebiten.SetWindowSize(c.WindowWidth, c.WindowHeight)
+	ebiten.SetFullscreen(c.Fullscreen)
+	ebiten.SetVsyncEnabled(c.VSync)
+}
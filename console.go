@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Console tuning
+const (
+	consoleFontSize   = 18.0 // In virtual pixels
+	consoleHeight     = 0.4  // Fraction of the screen the console covers
+	consoleMargin     = 12.0 // In virtual pixels
+	consoleMaxLog     = 200  // Output lines kept
+	consoleMaxHistory = 50   // Commands kept for the up and down keys
+	consoleToggleKey  = ebiten.KeyBackquote
+)
+
+var (
+	consoleBackgroundColor = color.RGBA{0, 0, 0, 200}
+	consoleTextColor       = color.RGBA{220, 220, 220, 255}
+	consoleInputColor      = color.RGBA{255, 255, 150, 255}
+)
+
+// ConsoleCommand is a command the console can run
+type ConsoleCommand struct {
+	Usage string   // How to call it, e.g. "gold <amount>"
+	Help  string   // One line saying what it does
+	Args  []string // Words autocomplete offers for the first argument
+	// Run carries out the command and returns a line to print
+	Run func(args []string) (string, error)
+}
+
+// Console is a developer console toggled with the backtick key. It runs commands
+// registered by the scene, keeps a history browsed with the up and down keys and
+// completes command names and arguments with Tab. While it is open it has the
+// keyboard to itself.
+type Console struct {
+	Open       bool
+	input      string
+	log        []string
+	history    []string
+	historyPos int // Position while browsing history; len(history) when not browsing
+	commands   map[string]*ConsoleCommand
+	names      []string // Command names, sorted for help and autocomplete
+	chars      []rune   // Typed characters, reused each tick
+	face       *text.GoTextFace
+}
+
+// NewConsole creates a closed console with the help and clear commands
+func NewConsole() *Console {
+	c := &Console{commands: make(map[string]*ConsoleCommand)}
+	c.Register("help", &ConsoleCommand{
+		Usage: "help",
+		Help:  "List the commands",
+		Run: func(args []string) (string, error) {
+			for _, name := range c.names {
+				cmd := c.commands[name]
+				c.Print(fmt.Sprintf("  %-26s %s", cmd.Usage, cmd.Help))
+			}
+			return "", nil
+		},
+	})
+	c.Register("clear", &ConsoleCommand{
+		Usage: "clear",
+		Help:  "Clear the console",
+		Run: func(args []string) (string, error) {
+			c.log = c.log[:0]
+			return "", nil
+		},
+	})
+	return c
+}
+
+// Register adds a command under a name, replacing any with the same name
+func (c *Console) Register(name string, cmd *ConsoleCommand) {
+	if _, ok := c.commands[name]; !ok {
+		c.names = append(c.names, name)
+		slices.Sort(c.names)
+	}
+	c.commands[name] = cmd
+}
+
+// Print adds a line to the console output
+func (c *Console) Print(line string) {
+	c.log = append(c.log, line)
+	if len(c.log) > consoleMaxLog {
+		c.log = slices.Delete(c.log, 0, len(c.log)-consoleMaxLog)
+	}
+}
+
+// Update handles the toggle key and, while the console is open, typing. It
+// reports whether the console had the keyboard this tick, in which case the
+// scene should ignore keys and the mouse.
+func (c *Console) Update() bool {
+	if inpututil.IsKeyJustPressed(consoleToggleKey) {
+		c.Open = !c.Open
+		c.historyPos = len(c.history)
+		return true
+	}
+	if !c.Open {
+		return false
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		c.Open = false
+		return true
+	}
+
+	c.chars = ebiten.AppendInputChars(c.chars[:0])
+	for _, r := range c.chars {
+		if r != '`' && r != '~' { // The toggle key types these
+			c.input += string(r)
+		}
+	}
+	if keyRepeated(ebiten.KeyBackspace) && c.input != "" {
+		runes := []rune(c.input)
+		c.input = string(runes[:len(runes)-1])
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		c.submit()
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		c.complete()
+	case keyRepeated(ebiten.KeyArrowUp) && c.historyPos > 0:
+		c.historyPos--
+		c.input = c.history[c.historyPos]
+	case keyRepeated(ebiten.KeyArrowDown) && c.historyPos < len(c.history):
+		c.historyPos++
+		c.input = ""
+		if c.historyPos < len(c.history) {
+			c.input = c.history[c.historyPos]
+		}
+	}
+	return true
+}
+
+// keyRepeated reports a key press and then repeats it while the key is held
+func keyRepeated(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d >= 30 && d%3 == 0)
+}
+
+// submit runs the typed line and remembers it
+func (c *Console) submit() {
+	line := strings.TrimSpace(c.input)
+	c.input = ""
+	if line == "" {
+		return
+	}
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+		if len(c.history) > consoleMaxHistory {
+			c.history = slices.Delete(c.history, 0, len(c.history)-consoleMaxHistory)
+		}
+	}
+	c.historyPos = len(c.history)
+	c.Print("> " + line)
+	c.Execute(line)
+}
+
+// Execute runs one command line and prints what it returns
+func (c *Console) Execute(line string) {
+	words := strings.Fields(line)
+	if len(words) == 0 {
+		return
+	}
+	cmd, ok := c.commands[strings.ToLower(words[0])]
+	if !ok {
+		c.Print(fmt.Sprintf("Unknown command %q; try help", words[0]))
+		return
+	}
+	result, err := cmd.Run(words[1:])
+	if err != nil {
+		c.Print("Error: " + err.Error())
+		c.Print("Usage: " + cmd.Usage)
+		return
+	}
+	if result != "" {
+		c.Print(result)
+	}
+}
+
+// complete finishes the word being typed: a command name, or the first argument
+// of a command that offers some. With several matches it completes as far as they
+// agree and lists them.
+func (c *Console) complete() {
+	words := strings.Fields(c.input)
+	startingWord := c.input == "" || strings.HasSuffix(c.input, " ")
+	if startingWord {
+		words = append(words, "")
+	}
+
+	var options []string
+	switch len(words) {
+	case 1:
+		options = c.names
+	case 2:
+		if cmd, ok := c.commands[strings.ToLower(words[0])]; ok {
+			options = cmd.Args
+		}
+	}
+
+	prefix := words[len(words)-1]
+	var matches []string
+	for _, option := range options {
+		if strings.HasPrefix(option, strings.ToLower(prefix)) {
+			matches = append(matches, option)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	words[len(words)-1] = common
+	c.input = strings.Join(words, " ")
+	if len(matches) == 1 {
+		c.input += " "
+	} else if common == prefix {
+		c.Print(strings.Join(matches, "  "))
+	}
+}
+
+// Draw draws the console across the top of the screen when it is open
+func (c *Console) Draw(screen *ebiten.Image, scale float64) {
+	if !c.Open {
+		return
+	}
+	size := max(consoleFontSize*scale, 8)
+	if c.face == nil || c.face.Size != size {
+		c.face = uiFont(regularFontSource, consoleFontSize, scale)
+	}
+
+	w := float64(screen.Bounds().Dx())
+	h := float64(screen.Bounds().Dy()) * consoleHeight
+	vector.DrawFilledRect(screen, 0, 0, float32(w), float32(h), consoleBackgroundColor, false)
+
+	margin := consoleMargin * scale
+	lineHeight := c.face.Size * 1.3
+	var opts text.DrawOptions
+
+	// The prompt sits at the bottom with the newest output above it
+	y := h - margin - lineHeight
+	opts.GeoM.Translate(margin, y)
+	opts.ColorScale.ScaleWithColor(consoleInputColor)
+	text.Draw(screen, "> "+c.input+"_", c.face, &opts)
+
+	for i := len(c.log) - 1; i >= 0; i-- {
+		y -= lineHeight
+		if y < margin {
+			break
+		}
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(margin, y)
+		opts.ColorScale.Reset()
+		opts.ColorScale.ScaleWithColor(consoleTextColor)
+		text.Draw(screen, c.log[i], c.face, &opts)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// consoleMaxSpeed is the fastest simulation speed the speed command allows
+const consoleMaxSpeed = 16
+
+// consoleCreepTypes are the creeps the spawn command knows about
+var consoleCreepTypes = []string{"firebug"}
+
+// errConsoleArgs is returned by a console command given the wrong arguments
+var errConsoleArgs = errors.New("wrong arguments")
+
+// registerConsoleCommands adds the cheat commands testers use to reach game
+// states quickly. They act on whichever session is current when they run.
+func (g *GameScene) registerConsoleCommands() {
+	g.console.Register("gold", &ConsoleCommand{
+		Usage: "gold <amount>",
+		Help:  "Set the player's gold",
+		Run: func(args []string) (string, error) {
+			amount, err := consoleInt(args, 1, 1, 0)
+			if err != nil {
+				return "", err
+			}
+			s := g.session
+			s.addGold(amount - s.currentGold)
+			return fmt.Sprintf("Gold set to %d", amount), nil
+		},
+	})
+	g.console.Register("health", &ConsoleCommand{
+		Usage: "health <amount>",
+		Help:  "Set the player's health, raising the maximum if needed",
+		Run: func(args []string) (string, error) {
+			amount, err := consoleInt(args, 1, 1, 1)
+			if err != nil {
+				return "", err
+			}
+			s := g.session
+			s.playerHealth = amount
+			s.maxHealth = max(s.maxHealth, amount)
+			return fmt.Sprintf("Health set to %d", amount), nil
+		},
+	})
+	g.console.Register("spawn", &ConsoleCommand{
+		Usage: "spawn <creep> [count]",
+		Help:  "Send creeps down the path",
+		Args:  consoleCreepTypes,
+		Run: func(args []string) (string, error) {
+			if len(args) < 1 || len(args) > 2 || !containsFold(consoleCreepTypes, args[0]) {
+				return "", fmt.Errorf("creep must be one of %s", strings.Join(consoleCreepTypes, ", "))
+			}
+			count := 1
+			if len(args) == 2 {
+				var err error
+				if count, err = consoleInt(args, 2, 2, 1); err != nil {
+					return "", err
+				}
+			}
+			if !g.session.spawnCreeps(count) {
+				return "", errors.New("the level has no path to spawn on")
+			}
+			return fmt.Sprintf("Spawned %d %s", count, strings.ToLower(args[0])), nil
+		},
+	})
+	g.console.Register("wave", &ConsoleCommand{
+		Usage: "wave <number>",
+		Help:  "Jump to a wave and send it now",
+		Run: func(args []string) (string, error) {
+			wave, err := consoleInt(args, 1, 1, 1)
+			if err != nil {
+				return "", err
+			}
+			g.session.jumpToWave(wave)
+			return fmt.Sprintf("Wave %d started", wave), nil
+		},
+	})
+	g.console.Register("god", &ConsoleCommand{
+		Usage: "god",
+		Help:  "Toggle taking no damage from escaping creeps",
+		Run: func(args []string) (string, error) {
+			if len(args) != 0 {
+				return "", errConsoleArgs
+			}
+			g.session.god = !g.session.god
+			return "God mode " + strings.ToLower(onOff(g.session.god)), nil
+		},
+	})
+	g.console.Register("speed", &ConsoleCommand{
+		Usage: fmt.Sprintf("speed <1-%d>", consoleMaxSpeed),
+		Help:  "Set the simulation speed",
+		Run: func(args []string) (string, error) {
+			speed, err := consoleInt(args, 1, 1, 1)
+			if err != nil {
+				return "", err
+			}
+			if speed > consoleMaxSpeed {
+				return "", fmt.Errorf("speed can be at most %d", consoleMaxSpeed)
+			}
+			g.session.setSpeed(speed)
+			return fmt.Sprintf("Speed set to %dx", speed), nil
+		},
+	})
+	g.console.Register("kill", &ConsoleCommand{
+		Usage: "kill all|<creep id>",
+		Help:  "Kill every creep, or one by ID; kills pay out gold as usual",
+		Args:  []string{"all"},
+		Run: func(args []string) (string, error) {
+			if len(args) != 1 {
+				return "", errConsoleArgs
+			}
+			if strings.EqualFold(args[0], "all") {
+				return fmt.Sprintf("Killed %d creeps", g.session.killCreeps(0)), nil
+			}
+			id, err := consoleInt(args, 1, 1, 1)
+			if err != nil {
+				return "", err
+			}
+			if g.session.killCreeps(id) == 0 {
+				return "", fmt.Errorf("no live creep with ID %d", id)
+			}
+			return fmt.Sprintf("Killed creep %d", id), nil
+		},
+	})
+	g.console.Register("tower", &ConsoleCommand{
+		Usage: "tower <type> at <x> <y>",
+		Help:  "Build a tower instantly and for free",
+		Run: func(args []string) (string, error) {
+			if len(args) == 4 && strings.EqualFold(args[1], "at") {
+				args = []string{args[0], args[2], args[3]} // "at" is optional
+			}
+			if len(args) != 3 {
+				return "", errConsoleArgs
+			}
+			towerID, err := consoleInt(args, 1, 3, 1)
+			if err != nil {
+				return "", err
+			}
+			col, err := consoleInt(args, 2, 3, 0)
+			if err != nil {
+				return "", err
+			}
+			row, err := consoleInt(args, 3, 3, 0)
+			if err != nil {
+				return "", err
+			}
+			s := g.session
+			if err := s.towerManager.BuildInstantly(col, row, towerID, s.level); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Built %s at %d,%d", towerDefinitions[towerID].Name, col, row), nil
+		},
+	})
+	g.console.Register("reload", &ConsoleCommand{
+		Usage: "reload",
+		Help:  "Reload the sprites from the assets, picking up edited files; new sprites use them",
+		Run: func(args []string) (string, error) {
+			if len(args) != 0 {
+				return "", errConsoleArgs
+			}
+			gameAssets.Reload()
+			lib, err := gameAssets.Animations()
+			if err != nil {
+				return "", err
+			}
+			reloaded, err := loadArt(lib)
+			if err != nil {
+				return "", err
+			}
+			art = reloaded
+			return fmt.Sprintf("Reloaded %d clips", len(lib.Names())), nil
+		},
+	})
+}
+
+// consoleInt parses argument n (counting from 1) as an integer of at least minValue.
+// count is the number of arguments the command takes, or 0 to not check it.
+func consoleInt(args []string, n, count, minValue int) (int, error) {
+	if (count > 0 && len(args) != count) || len(args) < n {
+		return 0, errConsoleArgs
+	}
+	value, err := strconv.Atoi(args[n-1])
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", args[n-1])
+	}
+	if value < minValue {
+		return 0, fmt.Errorf("%d is too small; the least is %d", value, minValue)
+	}
+	return value, nil
+}
+
+// containsFold reports whether list holds s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"math"
+	"towerDefense/tiled"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ViewTransform converts between the three coordinate spaces the game uses:
+//
+//   - tile:   map cells; creeps and projectiles keep fractional tile positions
+//   - world:  map pixels, tile * tile size (what sprites are authored in)
+//   - screen: logical screen pixels, world * Scale + Offset (what the camera produces)
+//
+// Every draw and input path goes through the one built into RenderParams so
+// they all agree, whatever the tile size of the level.
+type ViewTransform struct {
+	TileWidth, TileHeight float64 // World pixels per tile
+	Scale                 float64 // Screen pixels per world pixel
+	OffsetX, OffsetY      float64 // Screen position of the world origin
+	Cols, Rows            int     // Map size in tiles
+}
+
+// NewViewTransform builds the transform for the world view described by params
+func NewViewTransform(params RenderParams, level *tiled.TilemapJSON) ViewTransform {
+	cols, rows := level.MapSize()
+	return ViewTransform{
+		TileWidth:  float64(level.TileWidth),
+		TileHeight: float64(level.TileHeight),
+		Scale:      params.Scale,
+		OffsetX:    params.OffsetX,
+		OffsetY:    params.OffsetY,
+		Cols:       cols,
+		Rows:       rows,
+	}
+}
+
+// TileToWorld converts a (fractional) tile position to world pixels
+func (vt ViewTransform) TileToWorld(tileX, tileY float64) (float64, float64) {
+	return tileX * vt.TileWidth, tileY * vt.TileHeight
+}
+
+// WorldToTile converts world pixels to a fractional tile position
+func (vt ViewTransform) WorldToTile(worldX, worldY float64) (float64, float64) {
+	return worldX / vt.TileWidth, worldY / vt.TileHeight
+}
+
+// TileCenterWorld returns the world position of the centre of a tile
+func (vt ViewTransform) TileCenterWorld(col, row int) (float64, float64) {
+	return vt.TileToWorld(float64(col)+0.5, float64(row)+0.5)
+}
+
+// WorldToScreen converts world pixels to screen pixels
+func (vt ViewTransform) WorldToScreen(worldX, worldY float64) (float64, float64) {
+	return worldX*vt.Scale + vt.OffsetX, worldY*vt.Scale + vt.OffsetY
+}
+
+// ScreenToWorld converts screen pixels to world pixels
+func (vt ViewTransform) ScreenToWorld(screenX, screenY float64) (float64, float64) {
+	return (screenX - vt.OffsetX) / vt.Scale, (screenY - vt.OffsetY) / vt.Scale
+}
+
+// TileToScreen converts a (fractional) tile position to screen pixels
+func (vt ViewTransform) TileToScreen(tileX, tileY float64) (float64, float64) {
+	return vt.WorldToScreen(vt.TileToWorld(tileX, tileY))
+}
+
+// ScreenToTile returns the tile under a screen position; it may lie off the map
+func (vt ViewTransform) ScreenToTile(screenX, screenY float64) (int, int) {
+	tileX, tileY := vt.WorldToTile(vt.ScreenToWorld(screenX, screenY))
+	return int(math.Floor(tileX)), int(math.Floor(tileY))
+}
+
+// TileAtScreen returns the tile under a screen position and whether it is on the map
+func (vt ViewTransform) TileAtScreen(screenX, screenY int) (int, int, bool) {
+	col, row := vt.ScreenToTile(float64(screenX), float64(screenY))
+	return col, row, vt.ContainsTile(col, row)
+}
+
+// ContainsTile reports whether a tile lies on the map
+func (vt ViewTransform) ContainsTile(col, row int) bool {
+	return col >= 0 && row >= 0 && col < vt.Cols && row < vt.Rows
+}
+
+// ContainsScreen reports whether a screen position is over the map
+func (vt ViewTransform) ContainsScreen(screenX, screenY int) bool {
+	_, _, ok := vt.TileAtScreen(screenX, screenY)
+	return ok
+}
+
+// ScreenHitsTileCircle reports whether a screen position lies within radius tiles of a tile position
+func (vt ViewTransform) ScreenHitsTileCircle(screenX, screenY int, tileX, tileY, radius float64) bool {
+	worldX, worldY := vt.ScreenToWorld(float64(screenX), float64(screenY))
+	pointX, pointY := vt.WorldToTile(worldX, worldY)
+	return math.Hypot(pointX-tileX, pointY-tileY) <= radius
+}
+
+// Apply appends the world-to-screen transform to geom. Position the sprite in
+// world pixels first, then call Apply.
+func (vt ViewTransform) Apply(geom *ebiten.GeoM) {
+	geom.Scale(vt.Scale, vt.Scale)
+	geom.Translate(vt.OffsetX, vt.OffsetY)
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"math"
+	"slices"
+)
+
+// creepGridCellSize is the width and height of a grid cell in tiles. Two tiles
+// keeps a tower's five-tile range query to about 36 cells.
+const creepGridCellSize = 2.0
+
+// CreepGrid is a uniform spatial hash of the creeps that can be hit, over tile
+// coordinates. CreepManager rebuilds it every tick, so tower targeting, projectile
+// collisions and splash damage only look at creeps in nearby cells instead of
+// scanning every creep.
+//
+// The creeps are stored sorted by cell: the creeps in cell i are
+// creeps[cellStart[i]:cellStart[i+1]]. Rebuilding reuses the slices, so it doesn't
+// allocate once they have grown to fit.
+type CreepGrid struct {
+	cols, rows int // Size in cells
+	cellStart  []int
+	creeps     []*Creep
+	cellOf     []int // Cell of each creep being indexed, reused while rebuilding
+}
+
+// NewCreepGrid creates an empty grid
+func NewCreepGrid() *CreepGrid {
+	return &CreepGrid{}
+}
+
+// Rebuild indexes the creeps that are active and not dying on a map of the given size in tiles
+func (g *CreepGrid) Rebuild(creeps []*Creep, mapCols, mapRows int) {
+	g.cols = max(int(math.Ceil(float64(mapCols)/creepGridCellSize)), 1)
+	g.rows = max(int(math.Ceil(float64(mapRows)/creepGridCellSize)), 1)
+	cells := g.cols * g.rows
+
+	// Count the creeps in each cell...
+	g.cellStart = resizeInts(g.cellStart, cells+1)
+	clear(g.cellStart)
+	g.cellOf = g.cellOf[:0]
+	for _, creep := range creeps {
+		if !creep.IsActive() || creep.IsDying {
+			continue
+		}
+		cell := g.cellAt(creep.X, creep.Y)
+		g.cellOf = append(g.cellOf, cell)
+		g.cellStart[cell+1]++
+	}
+
+	// ...turn the counts into where each cell starts...
+	for i := 1; i <= cells; i++ {
+		g.cellStart[i] += g.cellStart[i-1]
+	}
+
+	// ...and drop each creep into the next free slot of its cell
+	g.creeps = slices.Grow(g.creeps[:0], len(g.cellOf))[:len(g.cellOf)]
+	next := 0
+	for _, creep := range creeps {
+		if !creep.IsActive() || creep.IsDying {
+			continue
+		}
+		cell := g.cellOf[next]
+		next++
+		// cellStart[cell] is bumped as the cell fills, then shifted back below
+		g.creeps[g.cellStart[cell]] = creep
+		g.cellStart[cell]++
+	}
+	for i := cells; i > 0; i-- {
+		g.cellStart[i] = g.cellStart[i-1]
+	}
+	g.cellStart[0] = 0
+}
+
+// Len returns the number of creeps in the grid
+func (g *CreepGrid) Len() int {
+	return len(g.creeps)
+}
+
+// AppendInRadius appends the creeps within radius tiles of a tile position to dst
+// and returns it. Creeps killed earlier in the tick are still included, so skip
+// those that are IsDown where it matters.
+func (g *CreepGrid) AppendInRadius(dst []*Creep, x, y, radius float64) []*Creep {
+	if len(g.creeps) == 0 {
+		return dst
+	}
+	minCol, minRow := g.cellCoords(x-radius, y-radius)
+	maxCol, maxRow := g.cellCoords(x+radius, y+radius)
+	radiusSq := radius * radius
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			cell := row*g.cols + col
+			for _, creep := range g.creeps[g.cellStart[cell]:g.cellStart[cell+1]] {
+				dx, dy := creep.X-x, creep.Y-y
+				if dx*dx+dy*dy <= radiusSq {
+					dst = append(dst, creep)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// Nearest returns the creep closest to a tile position and its distance in tiles,
+// or nil when the grid is empty. It searches outwards ring by ring and stops once
+// no unsearched cell can hold anything closer.
+func (g *CreepGrid) Nearest(x, y float64) (*Creep, float64) {
+	if len(g.creeps) == 0 {
+		return nil, 0
+	}
+	centerCol, centerRow := g.cellCoords(x, y)
+	var nearest *Creep
+	nearestDist := math.MaxFloat64
+	maxRing := max(g.cols, g.rows)
+
+	for ring := 0; ring <= maxRing; ring++ {
+		for row := centerRow - ring; row <= centerRow+ring; row++ {
+			// The top and bottom rows of the ring are searched across; the rows between only at the two sides
+			step := 1
+			if row != centerRow-ring && row != centerRow+ring {
+				step = 2 * ring
+			}
+			for col := centerCol - ring; col <= centerCol+ring; col += step {
+				if col < 0 || row < 0 || col >= g.cols || row >= g.rows {
+					continue
+				}
+				cell := row*g.cols + col
+				for _, creep := range g.creeps[g.cellStart[cell]:g.cellStart[cell+1]] {
+					if dist := math.Hypot(creep.X-x, creep.Y-y); dist < nearestDist {
+						nearest, nearestDist = creep, dist
+					}
+				}
+			}
+		}
+		// Every cell outside this ring is at least ring cells away from the start
+		// cell's edge. Creeps off the map are clamped into edge cells, so only
+		// trust the bound once the position itself is inside the grid.
+		if nearest != nil && nearestDist <= float64(ring)*creepGridCellSize && g.insideGrid(x, y) {
+			break
+		}
+	}
+	return nearest, nearestDist
+}
+
+// cellAt returns the index of the cell holding a tile position, clamped to the grid
+func (g *CreepGrid) cellAt(x, y float64) int {
+	col, row := g.cellCoords(x, y)
+	return row*g.cols + col
+}
+
+// cellCoords returns the column and row of the cell holding a tile position, clamped to the grid
+func (g *CreepGrid) cellCoords(x, y float64) (int, int) {
+	col := min(max(int(math.Floor(x/creepGridCellSize)), 0), g.cols-1)
+	row := min(max(int(math.Floor(y/creepGridCellSize)), 0), g.rows-1)
+	return col, row
+}
+
+// insideGrid reports whether a tile position lies within the grid's cells
+func (g *CreepGrid) insideGrid(x, y float64) bool {
+	return x >= 0 && y >= 0 && x < float64(g.cols)*creepGridCellSize && y < float64(g.rows)*creepGridCellSize
+}
+
+// resizeInts returns s with length n, reusing its storage when it is big enough
+func resizeInts(s []int, n int) []int {
+	if cap(s) >= n {
+		return s[:n]
+	}
+	return make([]int, n)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// Benchmark map size in tiles, big enough to spread 10k creeps out the way a
+// long maze level would
+const (
+	benchMapCols = 100
+	benchMapRows = 100
+)
+
+// creepCounts are the crowd sizes the grid benchmarks run at
+var creepCounts = []int{100, 1000, 10000}
+
+// scatteredCreeps returns n live creeps spread over the map
+func scatteredCreeps(n int, rng *rand.Rand) []*Creep {
+	creeps := make([]*Creep, n)
+	for i := range creeps {
+		creeps[i] = &Creep{
+			ID:     i + 1,
+			X:      rng.Float64() * benchMapCols,
+			Y:      rng.Float64() * benchMapRows,
+			Health: 20,
+			Active: true,
+		}
+	}
+	return creeps
+}
+
+// linearInRadius is the scan over every creep that the grid replaced
+func linearInRadius(dst []*Creep, creeps []*Creep, x, y, radius float64) []*Creep {
+	for _, creep := range creeps {
+		if !creep.IsActive() || creep.IsDying {
+			continue
+		}
+		if math.Hypot(creep.X-x, creep.Y-y) <= radius {
+			dst = append(dst, creep)
+		}
+	}
+	return dst
+}
+
+// linearNearest is the nearest-creep scan that the grid replaced
+func linearNearest(creeps []*Creep, x, y float64) (*Creep, float64) {
+	var nearest *Creep
+	nearestDist := math.MaxFloat64
+	for _, creep := range creeps {
+		if !creep.IsActive() || creep.IsDying {
+			continue
+		}
+		if dist := math.Hypot(creep.X-x, creep.Y-y); dist < nearestDist {
+			nearest, nearestDist = creep, dist
+		}
+	}
+	return nearest, nearestDist
+}
+
+func TestCreepGridMatchesLinearScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	creeps := scatteredCreeps(500, rng)
+	creeps[0].IsDying = true
+	creeps[1].X, creeps[1].Y = -3, 120 // Walking off the map
+	grid := NewCreepGrid()
+	grid.Rebuild(creeps, benchMapCols, benchMapRows)
+
+	if grid.Len() != len(creeps)-1 {
+		t.Fatalf("grid holds %d creeps, want %d without the dying one", grid.Len(), len(creeps)-1)
+	}
+	byID := func(a, b *Creep) int { return a.ID - b.ID }
+	for i := 0; i < 200; i++ {
+		x, y := rng.Float64()*130-15, rng.Float64()*130-15
+		radius := rng.Float64() * 8
+
+		got := grid.AppendInRadius(nil, x, y, radius)
+		want := linearInRadius(nil, creeps, x, y, radius)
+		slices.SortFunc(got, byID)
+		slices.SortFunc(want, byID)
+		if !slices.Equal(got, want) {
+			t.Fatalf("AppendInRadius(%.1f, %.1f, %.1f) found %d creeps, want %d", x, y, radius, len(got), len(want))
+		}
+
+		_, gotDist := grid.Nearest(x, y)
+		_, wantDist := linearNearest(creeps, x, y)
+		if gotDist != wantDist {
+			t.Fatalf("Nearest(%.1f, %.1f) is %.3f away, want %.3f", x, y, gotDist, wantDist)
+		}
+	}
+}
+
+func BenchmarkCreepGridRebuild(b *testing.B) {
+	for _, n := range creepCounts {
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			creeps := scatteredCreeps(n, rand.New(rand.NewSource(1)))
+			grid := NewCreepGrid()
+			grid.Rebuild(creeps, benchMapCols, benchMapRows) // Grow the storage
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				grid.Rebuild(creeps, benchMapCols, benchMapRows)
+			}
+		})
+	}
+}
+
+// BenchmarkCreepGridInRadius queries a tower's range around random points
+func BenchmarkCreepGridInRadius(b *testing.B) {
+	const towerRange = 5.0
+	for _, n := range creepCounts {
+		rng := rand.New(rand.NewSource(1))
+		creeps := scatteredCreeps(n, rng)
+		grid := NewCreepGrid()
+		grid.Rebuild(creeps, benchMapCols, benchMapRows)
+		points := randomPoints(rng, 1024)
+
+		b.Run(fmt.Sprintf("grid/%d", n), func(b *testing.B) {
+			var found []*Creep
+			b.ReportAllocs()
+			for i := range b.N {
+				p := points[i%len(points)]
+				found = grid.AppendInRadius(found[:0], p[0], p[1], towerRange)
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			var found []*Creep
+			b.ReportAllocs()
+			for i := range b.N {
+				p := points[i%len(points)]
+				found = linearInRadius(found[:0], creeps, p[0], p[1], towerRange)
+			}
+		})
+	}
+}
+
+func BenchmarkCreepGridNearest(b *testing.B) {
+	for _, n := range creepCounts {
+		rng := rand.New(rand.NewSource(1))
+		creeps := scatteredCreeps(n, rng)
+		grid := NewCreepGrid()
+		grid.Rebuild(creeps, benchMapCols, benchMapRows)
+		points := randomPoints(rng, 1024)
+
+		b.Run(fmt.Sprintf("grid/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := range b.N {
+				p := points[i%len(points)]
+				grid.Nearest(p[0], p[1])
+			}
+		})
+		b.Run(fmt.Sprintf("linear/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := range b.N {
+				p := points[i%len(points)]
+				linearNearest(creeps, p[0], p[1])
+			}
+		})
+	}
+}
+
+// randomPoints returns tile positions spread over the map
+func randomPoints(rng *rand.Rand, n int) [][2]float64 {
+	points := make([][2]float64, n)
+	for i := range points {
+		points[i] = [2]float64{rng.Float64() * benchMapCols, rng.Float64() * benchMapRows}
+	}
+	return points
+}
@@ -1,67 +1,107 @@
 package main
 
 import (
+	"image/color"
 	"math/rand"
+	"towerDefense/tiled"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Creep health bar colours
+var (
+	creepHealthFillColor  = color.RGBA{R: 90, G: 220, B: 90, A: 255}
+	creepHealthEmptyColor = color.RGBA{R: 60, G: 20, B: 20, A: 200}
 )
 
 // CreepManager handles spawning creeps and tracking their count
 type CreepManager struct {
-	creeps        []*Creep
-	onCreepEscape func(damage float64)
-	onCreepKilled func(goldReward int)
-	nextCreepID   int
+	creeps      []*Creep
+	events      *EventBus // Spawns, kills and escapes are published here
+	nextCreepID int
+	grid        *CreepGrid // Where the creeps that can be hit are, rebuilt every update
+	free        []*Creep   // Finished creeps kept for reuse by the next spawns
 }
 
-func NewCreepManager() *CreepManager {
+func NewCreepManager(events *EventBus) *CreepManager {
 	return &CreepManager{
+		events:      events,
 		nextCreepID: 1,
+		grid:        NewCreepGrid(),
 	}
 }
 
-// SetOnCreepEscape sets the callback for when a creep escapes
-func (cm *CreepManager) SetOnCreepEscape(cb func(damage float64)) {
-	cm.onCreepEscape = cb
-}
-
-// SetOnCreepKilled sets the callback for when a creep is killed
-func (cm *CreepManager) SetOnCreepKilled(cb func(goldReward int)) {
-	cm.onCreepKilled = cb
-}
-
 // SpawnCreeps creates and adds creeps to the manager
-func SpawnCreeps(manager *CreepManager, numCreeps int, startX, startY float64, pathNodes []PathNode) {
+func SpawnCreeps(manager *CreepManager, numCreeps int, startX, startY float64, pathNodes []tiled.PathNode) {
 	if manager == nil {
 		return
 	}
 
 	for i := 0; i < numCreeps; i++ {
 		startDelay := 1.0 + rand.Float64()*4.0 // Random delay 1-5 seconds
-		creep := NewCreep(manager.GetNextCreepID(), startX, startY, pathNodes, startDelay)
-		manager.AddCreep(creep)
+		manager.AddCreep(manager.newCreep(startX, startY, pathNodes, startDelay))
+	}
+}
+
+// newCreep readies a creep from the pool, or a new one when the pool is empty
+func (cm *CreepManager) newCreep(x, y float64, path []tiled.PathNode, startDelay float64) *Creep {
+	var creep *Creep
+	if n := len(cm.free); n > 0 {
+		creep = cm.free[n-1]
+		cm.free[n-1] = nil
+		cm.free = cm.free[:n-1]
+	} else {
+		creep = &Creep{}
 	}
+	creep.reset(cm.GetNextCreepID(), x, y, path, startDelay)
+	return creep
 }
 
-func (cm *CreepManager) Update(level *TilemapJSON, deltaTime float64) {
-	var remainingCreeps []*Creep
+func (cm *CreepManager) Update(level *tiled.TilemapJSON, deltaTime float64) {
+	// Creeps still active are moved down over finished ones, which go back to the pool
+	kept := 0
 	for _, creep := range cm.creeps {
 		if creep.IsActive() {
-			// Create a callback function to handle escapes
-			onEscape := func(damage float64) {
-				if cm.onCreepEscape != nil {
-					cm.onCreepEscape(damage)
-				}
-
+			// Publish what happened to this creep
+			switch creep.Update(deltaTime, level) {
+			case creepWasKilled:
+				cm.events.CreepKilled.Publish(CreepKilled{CreepID: creep.ID, X: creep.X, Y: creep.Y, GoldReward: creepGoldReward})
+			case creepGotAway:
+				cm.events.CreepEscaped.Publish(CreepEscaped{CreepID: creep.ID, X: creep.X, Y: creep.Y, Damage: creep.Damage})
 			}
-			creep.Update(deltaTime, level, onEscape, cm.onCreepKilled)
 		}
 		// Check if creep is still active after update (may have escaped)
 		if creep.IsActive() {
-			remainingCreeps = append(remainingCreeps, creep)
+			cm.creeps[kept] = creep
+			kept++
+		} else {
+			cm.free = append(cm.free, creep)
+		}
+	}
+	clear(cm.creeps[kept:])
+	cm.creeps = cm.creeps[:kept]
+
+	cols, rows := level.MapSize()
+	cm.grid.Rebuild(cm.creeps, cols, rows)
+}
+
+// Grid returns the spatial index of the creeps as of the last update
+func (cm *CreepManager) Grid() *CreepGrid {
+	return cm.grid
+}
+
+// RepathCreeps gives every creep still on its route a fresh path from the tile it is walking into.
+// Creeps keep their old path if findPath returns nil.
+func (cm *CreepManager) RepathCreeps(findPath func(from tiled.PathNode) []tiled.PathNode) {
+	for _, creep := range cm.creeps {
+		if !creep.IsActive() || creep.IsDying || creep.HasFinishedPath() {
+			continue
+		}
+		if path := findPath(creep.NextTile()); path != nil {
+			creep.SetPath(path)
 		}
 	}
-	cm.creeps = remainingCreeps
 }
 
 // Draw renders all creeps
@@ -73,9 +113,41 @@ func (cm *CreepManager) Draw(screen *ebiten.Image, params RenderParams) {
 	}
 }
 
+// DrawHealthBars draws a small health bar over each creep that has taken damage
+func (cm *CreepManager) DrawHealthBars(screen *ebiten.Image, params RenderParams) {
+	const barWidth, barHeight = 0.6, 0.08 // In tiles
+	for _, creep := range cm.creeps {
+		if !creep.IsActive() || creep.IsDying || creep.Health >= creep.MaxHealth {
+			continue
+		}
+		// Creep positions are the top-left of the sprite; centre the bar just above it
+		x, y := params.View.TileToScreen(creep.X+(1-barWidth)/2, creep.Y-barHeight)
+		w := barWidth * params.View.TileWidth * params.View.Scale
+		h := barHeight * params.View.TileHeight * params.View.Scale
+		fraction := creep.Health / creep.MaxHealth
+
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), creepHealthEmptyColor, false)
+		vector.DrawFilledRect(screen, float32(x), float32(y), float32(w*fraction), float32(h), creepHealthFillColor, false)
+	}
+}
+
 // AddCreep adds a new creep
 func (cm *CreepManager) AddCreep(creep *Creep) {
 	cm.creeps = append(cm.creeps, creep)
+	cm.events.CreepSpawned.Publish(CreepSpawned{CreepID: creep.ID, X: creep.X, Y: creep.Y})
+}
+
+// creepByID returns the live creep with the given ID, or nil
+func (cm *CreepManager) creepByID(id int) *Creep {
+	if id == 0 {
+		return nil
+	}
+	for _, creep := range cm.creeps {
+		if creep.ID == id {
+			return creep
+		}
+	}
+	return nil
 }
 
 // GetNextCreepID provides a unique ID for a new creep
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"towerDefense/assets"
+	"towerDefense/tiled"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// benchTick is the length of a tick at the game's default 60 TPS
+const benchTick = 1.0 / 60
+
+// useTestArt sets the global art to clips with the game's frame counts but no
+// images, so managers can be updated without a graphics context
+func useTestArt(tb testing.TB) {
+	tb.Helper()
+	clip := func(name string, frames int, duration float64, loop bool) *assets.Clip {
+		return &assets.Clip{Name: name, Frames: make([]*ebiten.Image, frames), Duration: duration, Loop: loop}
+	}
+	directional := func(prefix string, frames int, loop bool) DirectionalClip {
+		return DirectionalClip{
+			Side: clip(prefix+"/side", frames, 1, loop),
+			Up:   clip(prefix+"/up", frames, 1, loop),
+			Down: clip(prefix+"/down", frames, 1, loop),
+		}
+	}
+
+	old := art
+	art = &Art{
+		TowerBuild:         clip("tower/build", 6, 1, false),
+		TowerTransition:    clip("tower/transition", 5, 0.75, false),
+		BallistaFire:       clip("ballista/fire", 6, 0.5, false),
+		BallistaProjectile: clip("ballista/projectile", 3, 0.5, true),
+		BallistaImpact:     clip("ballista/impact", 6, 0.5, false),
+		MagicIdle:          clip("magic/idle", 8, 1, true),
+		MagicAttack:        clip("magic/attack", 27, 0.5, false),
+		MagicProjectile:    clip("magic/projectile", 12, 0.5, true),
+		MagicImpact:        clip("magic/impact", 10, 0.5, false),
+		Firebug: CreepArt{
+			Idle:  directional("firebug/idle", 5, true),
+			Walk:  directional("firebug/walk", 7, true),
+			Death: directional("firebug/death", 10, false),
+		},
+	}
+	tb.Cleanup(func() { art = old })
+}
+
+// benchLevel returns an open grass level of the given size in tiles
+func benchLevel(cols, rows int) *tiled.TilemapJSON {
+	data := make([]int, cols*rows)
+	for i := range data {
+		data[i] = 1
+	}
+	level := &tiled.TilemapJSON{
+		Width: cols, Height: rows, TileWidth: 16, TileHeight: 16,
+		Layers: []tiled.TilemapLayerJSON{{Name: "ground", Type: "tilelayer", Width: cols, Height: rows, Data: data}},
+	}
+	level.BuildTileFlags()
+	return level
+}
+
+// benchmarkSteadyState runs tick warmup times so pools and slices grow to fit,
+// then times it, failing the benchmark if any timed tick allocated
+func benchmarkSteadyState(b *testing.B, warmup int, tick func()) {
+	b.Helper()
+	for range warmup {
+		tick()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for range b.N {
+		tick()
+	}
+	runtime.ReadMemStats(&after)
+	b.StopTimer()
+
+	if allocs := after.Mallocs - before.Mallocs; allocs > 0 {
+		b.Fatalf("%d allocations over %d ticks once warmed up, want none", allocs, b.N)
+	}
+}
+
+// BenchmarkCreepManagerUpdate runs a wave that is topped up to a fixed crowd as
+// creeps are shot down or walk off the map, so creeps are constantly recycled
+func BenchmarkCreepManagerUpdate(b *testing.B) {
+	useTestArt(b)
+	level := benchLevel(40, 12)
+	path := []tiled.PathNode{{X: 0, Y: 6}, {X: 12, Y: 6}, {X: 12, Y: 2}, {X: 26, Y: 2}, {X: 26, Y: 9}, {X: 39, Y: 9}}
+
+	for _, crowd := range []int{100, 1000} {
+		b.Run(fmt.Sprint(crowd), func(b *testing.B) {
+			cm := NewCreepManager(NewEventBus())
+
+			// Fill the pool as a whole wave finishing at once would
+			SpawnCreeps(cm, crowd, 0, 6, path)
+			for _, creep := range cm.creeps {
+				creep.Active = false
+			}
+			cm.Update(level, benchTick)
+
+			shots := max(crowd/60, 1) // About one creep in six is hit each second
+			next := 0
+			benchmarkSteadyState(b, 60*60, func() {
+				SpawnCreeps(cm, crowd-len(cm.creeps), 0, 6, path)
+				for range shots {
+					next = (next + 7) % len(cm.creeps)
+					cm.creeps[next].TakeDamage(8)
+				}
+				cm.Update(level, benchTick)
+			})
+		})
+	}
+}
@@ -3,17 +3,11 @@ package main
 import (
 	"math"
 	"math/rand"
-	"towerDefense/assets"
+	"towerDefense/tiled"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// PathNode represents a single step in the path
-// This is one point in our waypointing logic
-type PathNode struct {
-	X, Y int
-}
-
 // Direction enum for sprite facing
 type Direction int
 
@@ -24,13 +18,26 @@ const (
 	DirectionDown
 )
 
+// creepGoldReward is the gold paid out when a creep is killed
+const creepGoldReward = 15
+
+// creepOutcome is what happened to a creep during an update that the rest of the
+// game needs to hear about
+type creepOutcome int
+
+const (
+	creepCarriedOn creepOutcome = iota
+	creepWasKilled              // Its health ran out and it started dying
+	creepGotAway                // It walked off the map
+)
+
 type Creep struct {
 	ID               int
 	X, Y             float64
 	Speed            float64
 	Health           float64
 	MaxHealth        float64
-	Path             []PathNode
+	Path             []tiled.PathNode
 	PathIndex        int
 	Animation        *AnimatedSprite
 	CurrentDirection Direction
@@ -39,19 +46,21 @@ type Creep struct {
 	Active           bool
 	Damage           float64
 	IsDying          bool
+	sprite           AnimatedSprite // Storage Animation points at, reused when the animation changes
 }
 
-func NewCreep(id int, x, y float64, path []PathNode, startDelay float64) *Creep {
-	return &Creep{
+// reset readies a new or recycled creep to walk a path. It keeps the creep's path
+// and sprite storage so recycling it doesn't allocate.
+func (c *Creep) reset(id int, x, y float64, path []tiled.PathNode, startDelay float64) {
+	*c = Creep{
 		ID:               id,
 		X:                x,
 		Y:                y,
 		Speed:            2.0 + rand.Float64()*2.0, // 2-4 speed range
 		Health:           20.0,
 		MaxHealth:        20.0,
-		Path:             append([]PathNode(nil), path...), // Copy path
+		Path:             append(c.Path[:0], path...), // Copy path
 		PathIndex:        0,
-		Animation:        NewAnimatedSprite(assets.FirebugSideIdle, 1.0, true),
 		CurrentDirection: DirectionRight,
 		StartDelay:       startDelay,
 		Timer:            0,
@@ -59,6 +68,8 @@ func NewCreep(id int, x, y float64, path []PathNode, startDelay float64) *Creep
 		Damage:           2.0,
 		IsDying:          false,
 	}
+	c.sprite.Reset(art.Firebug.Idle.Side)
+	c.Animation = &c.sprite
 }
 
 // IsActive returns if the creep is still active
@@ -66,11 +77,64 @@ func (c *Creep) IsActive() bool {
 	return c.Active
 }
 
+// IsDown reports whether the creep is out of the fight: dying, or killed earlier
+// this tick and due to start dying on its next update
+func (c *Creep) IsDown() bool {
+	return c.IsDying || c.Health <= 0
+}
+
 // GetID returns the creep's ID
 func (c *Creep) GetID() int {
 	return c.ID
 }
 
+// CurrentTile returns the grid cell the creep is closest to
+func (c *Creep) CurrentTile() tiled.PathNode {
+	return tiled.PathNode{X: int(math.Round(c.X)), Y: int(math.Round(c.Y))}
+}
+
+// NextTile returns the path node the creep is walking into, or the tile it stands
+// on if it hasn't set off yet. Re-routed paths start here so the creep finishes
+// the step it is on.
+func (c *Creep) NextTile() tiled.PathNode {
+	if c.Timer < c.StartDelay || c.HasFinishedPath() {
+		return c.CurrentTile()
+	}
+	return c.Path[c.PathIndex+1]
+}
+
+// HasFinishedPath reports whether the creep is past its last waypoint and heading off the map
+func (c *Creep) HasFinishedPath() bool {
+	return c.PathIndex >= len(c.Path)-1
+}
+
+// RemainingPathDistance returns how far, in tiles, the creep still has to walk to its last waypoint
+func (c *Creep) RemainingPathDistance() float64 {
+	if c.HasFinishedPath() {
+		return 0
+	}
+	next := c.Path[c.PathIndex+1]
+	remaining := math.Hypot(float64(next.X)-c.X, float64(next.Y)-c.Y)
+	for i := c.PathIndex + 1; i < len(c.Path)-1; i++ {
+		remaining += math.Hypot(float64(c.Path[i+1].X-c.Path[i].X), float64(c.Path[i+1].Y-c.Path[i].Y))
+	}
+	return remaining
+}
+
+// SetPath re-routes the creep along a path starting at its NextTile. The node it
+// last passed is kept in front, so it carries on into the next tile rather than
+// turning back or cutting across to the nearest tile centre.
+func (c *Creep) SetPath(path []tiled.PathNode) {
+	if len(c.Path) == 0 || len(path) == 0 || c.Path[c.PathIndex] == path[0] {
+		c.Path = append(c.Path[:0], path...)
+		c.PathIndex = 0
+		return
+	}
+	from := c.Path[c.PathIndex]
+	c.Path = append(append(c.Path[:0], from), path...)
+	c.PathIndex = 0
+}
+
 // GetDamage returns the damage this creep deals when escaping
 func (c *Creep) GetDamage() float64 {
 	return c.Damage
@@ -101,22 +165,24 @@ func (c *Creep) Draw(screen *ebiten.Image, params RenderParams) {
 		return
 	}
 
-	//Maybe refactor this later to a centeral location
-	tileSize := 64.0
-
+	// Place the sprite's pivot on the creep's tile position, then into screen space
+	pivotX, pivotY := c.Animation.Pivot()
 	opts := &ebiten.DrawImageOptions{}
-
-	// Calculate position
-	worldX := c.X * tileSize
-	worldY := c.Y * tileSize
-	screenX := params.OffsetX + worldX*params.Scale
-	screenY := params.OffsetY + worldY*params.Scale
-	opts.GeoM.Scale(params.Scale, params.Scale)
-	opts.GeoM.Translate(screenX, screenY)
+	opts.GeoM.Translate(-pivotX*float64(frame.Bounds().Dx()), -pivotY*float64(frame.Bounds().Dy()))
+	opts.GeoM.Translate(params.View.TileToWorld(c.X, c.Y))
+	params.View.Apply(&opts.GeoM)
 
 	screen.DrawImage(frame, opts)
 }
 
+// currentSpeed returns the creep's speed adjusted for the terrain it is standing on
+func (c *Creep) currentSpeed(level *tiled.TilemapJSON) float64 {
+	if level != nil && level.IsSlow(int(math.Floor(c.X)), int(math.Floor(c.Y))) {
+		return c.Speed * tiled.SlowTerrainSpeedFactor
+	}
+	return c.Speed
+}
+
 // updateDirection sets the creep's facing direction
 func (c *Creep) updateDirection(dx, dy float64) {
 	if math.Abs(dx) > math.Abs(dy) {
@@ -134,50 +200,19 @@ func (c *Creep) updateDirection(dx, dy float64) {
 	}
 }
 
-// animationFramesEqual checks if two animation frame sets are the same
-func animationFramesEqual(a, b []*ebiten.Image) bool {
-	if len(a) != len(b) {
-		return false
-	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
-		}
-	}
-	return true
-}
-
 // setAnimation sets the appropriate animation based on state and direction
 func (c *Creep) setAnimation() {
-	var frames []*ebiten.Image
-
 	// Choose animation based on movement state and direction
+	clips := art.Firebug.Idle
 	if c.PathIndex >= len(c.Path)-1 || (c.PathIndex < len(c.Path)-1 && c.Timer >= c.StartDelay) {
-		// Walking animation
-		switch c.CurrentDirection {
-		case DirectionUp:
-			frames = assets.FirebugUpWalk
-		case DirectionDown:
-			frames = assets.FirebugDownWalk
-		default: // Left/Right
-			frames = assets.FirebugSideWalk
-		}
-	} else {
-		// Idle animation
-		switch c.CurrentDirection {
-		case DirectionUp:
-			frames = assets.FirebugUpIdle
-		case DirectionDown:
-			frames = assets.FirebugDownIdle
-		default: // Left/Right
-			frames = assets.FirebugSideIdle
-		}
+		clips = art.Firebug.Walk
 	}
+	clip := clips.For(c.CurrentDirection)
 
 	// Only change animation if it's different
-	if c.Animation == nil || !animationFramesEqual(c.Animation.frames, frames) {
-		c.Animation = NewAnimatedSprite(frames, 1.0, true)
-		c.Animation.Play()
+	if !c.sprite.Uses(clip) {
+		c.sprite.Reset(clip)
+		c.sprite.Play()
 	}
 }
 
@@ -185,19 +220,17 @@ func (c *Creep) setAnimation() {
 // This function is called every frame to move the creep along its path
 // deltaTime: time elapsed since last frame (in seconds)
 // level: the game map containing boundaries and layout
-func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(float64), onKilled func(int)) {
+// It returns whether the creep was killed or got away, for the manager to announce.
+func (c *Creep) Update(deltaTime float64, level *tiled.TilemapJSON) creepOutcome {
 	// Update the internal timer - this tracks how long the creep has been alive
 	c.Timer += deltaTime
 
 	// Handle death
 	if c.Health <= 0 && !c.IsDying {
 		c.IsDying = true
-		c.Animation = NewAnimatedSprite(assets.FirebugSideDeath, 1.0, false)
-		c.Animation.Play()
-		if onKilled != nil {
-			onKilled(15) // Give 15 gold
-		}
-		return
+		c.sprite.Reset(art.Firebug.Death.Side)
+		c.sprite.Play()
+		return creepWasKilled
 	}
 
 	// If dying, just update animation and check if finished
@@ -206,7 +239,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 		if !c.Animation.IsPlaying() {
 			c.Active = false
 		}
-		return
+		return creepCarriedOn
 	}
 
 	// PHASE 1: Check if we should wait before starting to move
@@ -214,7 +247,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 	if c.Timer < c.StartDelay {
 		// Still waiting, just update animation and don't move yet
 		c.Animation.Update(deltaTime)
-		return
+		return creepCarriedOn
 	}
 
 	// PHASE 2: Safety check - make sure we have a path to follow
@@ -222,7 +255,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 	if len(c.Path) == 0 {
 		// No path available, just update animation
 		c.Animation.Update(deltaTime)
-		return
+		return creepCarriedOn
 	}
 
 	// PHASE 3: Main movement logic
@@ -239,7 +272,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 		// Only move if there's actually distance to cover
 		if distance > 0 {
 			// Calculate how far we can move this frame based on speed
-			moveDistance := c.Speed * deltaTime
+			moveDistance := c.currentSpeed(level) * deltaTime
 
 			// Check if we can reach the target this frame
 			if moveDistance >= distance {
@@ -280,7 +313,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 		}
 
 		// Continue moving in that direction to exit the screen
-		moveDistance := c.Speed * deltaTime
+		moveDistance := c.currentSpeed(level) * deltaTime
 		c.X += dx * moveDistance
 		c.Y += dy * moveDistance
 	}
@@ -295,11 +328,7 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 		if c.X < -1 || c.X > mapWidth || c.Y < -1 || c.Y > mapHeight {
 			// Creep has escaped! Mark it as inactive so it gets removed
 			c.Active = false
-			// TODO: This is where we would call a function to handle player losing health
-			if onEscape != nil {
-				onEscape(c.Damage)
-			}
-			return
+			return creepGotAway
 		}
 	}
 
@@ -308,4 +337,5 @@ func (c *Creep) Update(deltaTime float64, level *TilemapJSON, onEscape func(floa
 	c.setAnimation()
 	// Update the animation frames (for walking/idle cycles)
 	c.Animation.Update(deltaTime)
+	return creepCarriedOn
 }
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Debug overlay sizes, in UI pixels
+const (
+	debugFontSize  = 16.0
+	debugLineWidth = 2.0
+	debugMargin    = 12.0
+)
+
+// Debug overlay colours
+var (
+	debugBuildableColor   = color.RGBA{0, 160, 0, 50}
+	debugUnbuildableColor = color.RGBA{160, 0, 0, 50}
+	debugGridColor        = color.RGBA{255, 255, 255, 40}
+	debugPathColor        = color.RGBA{255, 220, 0, 255}
+	debugRangeColor       = color.RGBA{100, 180, 255, 200}
+	debugTargetColor      = color.RGBA{255, 80, 80, 255}
+	debugTrackColor       = color.RGBA{255, 80, 80, 90} // Target still out of range
+	debugCreepColor       = color.RGBA{255, 120, 0, 255}
+	debugProjectileColor  = color.RGBA{0, 255, 255, 255}
+	debugTextColor        = color.RGBA{255, 255, 255, 255}
+	debugPanelColor       = color.RGBA{0, 0, 0, 170}
+)
+
+// DebugOverlay draws what the simulation is doing over the map: which tiles can
+// be built on, the route creeps follow, each tower's range and target, the
+// collision circles of creeps and projectiles, creep IDs and health, and frame
+// rate and entity counts. The debug overlay key (F3 by default) toggles it.
+type DebugOverlay struct {
+	Visible bool
+	face    *text.GoTextFace
+}
+
+// NewDebugOverlay creates a hidden overlay
+func NewDebugOverlay() *DebugOverlay {
+	return &DebugOverlay{}
+}
+
+// Toggle shows or hides the overlay
+func (d *DebugOverlay) Toggle() {
+	d.Visible = !d.Visible
+}
+
+// Draw draws the overlay for the session, if it is visible
+func (d *DebugOverlay) Draw(screen *ebiten.Image, params RenderParams, s *GameSession) {
+	if !d.Visible {
+		return
+	}
+	size := max(debugFontSize*params.UIScale, 8)
+	if d.face == nil || d.face.Size != size {
+		d.face = uiFont(regularFontSource, debugFontSize, params.UIScale)
+	}
+	lineWidth := float32(max(debugLineWidth*params.UIScale, 1))
+
+	d.drawTiles(screen, params, s)
+	d.drawPath(screen, params, s, lineWidth)
+	d.drawTowers(screen, params, s, lineWidth)
+	d.drawCollisions(screen, params, s, lineWidth)
+	d.drawCreepLabels(screen, params, s)
+	d.drawStats(screen, params, s)
+}
+
+// drawTiles shades each tile by whether a tower could be built there and outlines the grid
+func (d *DebugOverlay) drawTiles(screen *ebiten.Image, params RenderParams, s *GameSession) {
+	view := params.View
+	tileW := float32(view.TileWidth * view.Scale)
+	tileH := float32(view.TileHeight * view.Scale)
+	for row := 0; row < view.Rows; row++ {
+		for col := 0; col < view.Cols; col++ {
+			x, y := view.TileToScreen(float64(col), float64(row))
+			fill := debugUnbuildableColor
+			if s.towerManager.isTileBuildable(col, row, s.level) {
+				fill = debugBuildableColor
+			}
+			vector.DrawFilledRect(screen, float32(x), float32(y), tileW, tileH, fill, false)
+			vector.StrokeRect(screen, float32(x), float32(y), tileW, tileH, 1, debugGridColor, false)
+		}
+	}
+}
+
+// drawPath draws the route new creeps follow, with a dot on each waypoint
+func (d *DebugOverlay) drawPath(screen *ebiten.Image, params RenderParams, s *GameSession, lineWidth float32) {
+	path := s.creepPath()
+	for i, node := range path {
+		x, y := params.View.TileToScreen(float64(node.X), float64(node.Y))
+		if i > 0 {
+			prevX, prevY := params.View.TileToScreen(float64(path[i-1].X), float64(path[i-1].Y))
+			vector.StrokeLine(screen, float32(prevX), float32(prevY), float32(x), float32(y), lineWidth, debugPathColor, true)
+		}
+		vector.DrawFilledCircle(screen, float32(x), float32(y), lineWidth*2, debugPathColor, true)
+	}
+}
+
+// drawTowers draws each tower's range and a line to the creep it is aiming at
+func (d *DebugOverlay) drawTowers(screen *ebiten.Image, params RenderParams, s *GameSession, lineWidth float32) {
+	view := params.View
+	for i := range s.towerManager.placedTowers {
+		tower := &s.towerManager.placedTowers[i]
+		centerX, centerY := float64(tower.X)+0.5, float64(tower.Y)+0.5
+		x, y := view.TileToScreen(centerX, centerY)
+		radius := tower.Range * view.TileWidth * view.Scale
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(radius), lineWidth, debugRangeColor, true)
+
+		target := s.creepManager.creepByID(tower.TargetCreepID)
+		if target == nil {
+			continue
+		}
+		lineColor := debugTargetColor
+		if math.Hypot(target.X-centerX, target.Y-centerY) > tower.Range {
+			lineColor = debugTrackColor
+		}
+		targetX, targetY := view.TileToScreen(target.X, target.Y)
+		vector.StrokeLine(screen, float32(x), float32(y), float32(targetX), float32(targetY), lineWidth, lineColor, true)
+	}
+}
+
+// drawCollisions draws the circles projectile hits are tested with. A hit lands
+// when a projectile's circle touches a creep's.
+func (d *DebugOverlay) drawCollisions(screen *ebiten.Image, params RenderParams, s *GameSession, lineWidth float32) {
+	view := params.View
+	tileScale := view.TileWidth * view.Scale
+	for _, creep := range s.creepManager.creeps {
+		if !creep.IsActive() || creep.IsDying {
+			continue
+		}
+		x, y := view.TileToScreen(creep.X, creep.Y)
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(creepCollisionRadius*tileScale), lineWidth, debugCreepColor, true)
+	}
+	for _, projectile := range s.towerManager.projectileManager.projectiles {
+		if !projectile.Active || projectile.IsImpacting {
+			continue
+		}
+		x, y := view.TileToScreen(projectile.X, projectile.Y)
+		vector.StrokeCircle(screen, float32(x), float32(y), float32(projectileCollisionRadius*tileScale), lineWidth, debugProjectileColor, true)
+	}
+}
+
+// drawCreepLabels writes each creep's ID and health above it
+func (d *DebugOverlay) drawCreepLabels(screen *ebiten.Image, params RenderParams, s *GameSession) {
+	var opts text.DrawOptions
+	for _, creep := range s.creepManager.creeps {
+		if !creep.IsActive() {
+			continue
+		}
+		x, y := params.View.TileToScreen(creep.X, creep.Y-creepCollisionRadius)
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(x, y-d.face.Size*1.5)
+		opts.PrimaryAlign = text.AlignCenter
+		opts.ColorScale.Reset()
+		opts.ColorScale.ScaleWithColor(debugTextColor)
+		text.Draw(screen, fmt.Sprintf("#%d %.0f/%.0f", creep.ID, creep.Health, creep.MaxHealth), d.face, &opts)
+	}
+}
+
+// drawStats writes the frame rates and entity counts in the bottom-left corner
+func (d *DebugOverlay) drawStats(screen *ebiten.Image, params RenderParams, s *GameSession) {
+	creeps := 0
+	for _, creep := range s.creepManager.creeps {
+		if creep.IsActive() {
+			creeps++
+		}
+	}
+	projectiles := 0
+	for _, projectile := range s.towerManager.projectileManager.projectiles {
+		if projectile.Active {
+			projectiles++
+		}
+	}
+	stats := fmt.Sprintf("FPS %.1f  TPS %.1f  Speed %dx\nWave %d  Creeps %d/%d  Towers %d  Building %d  Projectiles %d",
+		ebiten.ActualFPS(), ebiten.ActualTPS(), s.speed,
+		s.wave, creeps, len(s.creepManager.creeps), len(s.towerManager.placedTowers),
+		len(s.towerManager.buildingAnimations), projectiles)
+
+	lineSpacing := d.face.Size * 1.3
+	w, h := text.Measure(stats, d.face, lineSpacing)
+	margin := debugMargin * params.UIScale
+	x := margin
+	y := float64(params.ScreenHeight) - h - 2*margin
+	vector.DrawFilledRect(screen, float32(x-margin/2), float32(y-margin/2), float32(w+margin), float32(h+margin), debugPanelColor, false)
+
+	var opts text.DrawOptions
+	opts.GeoM.Translate(x, y)
+	opts.LineSpacing = lineSpacing
+	opts.ColorScale.ScaleWithColor(debugTextColor)
+	text.Draw(screen, stats, d.face, &opts)
+}
@@ -0,0 +1,127 @@
+package main
+
+// Topic is one kind of gameplay event. Handlers run straight away, in the order
+// they subscribed, on the goroutine that publishes.
+type Topic[E any] struct {
+	handlers []subscription[E]
+	nextID   int
+}
+
+type subscription[E any] struct {
+	id      int
+	handler func(E)
+}
+
+// Subscribe adds a handler for every future event on the topic. The returned
+// function removes it again.
+func (t *Topic[E]) Subscribe(handler func(E)) (unsubscribe func()) {
+	t.nextID++
+	id := t.nextID
+	t.handlers = append(t.handlers, subscription[E]{id: id, handler: handler})
+	return func() {
+		for i, s := range t.handlers {
+			if s.id == id {
+				t.handlers = append(t.handlers[:i:i], t.handlers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish calls every handler with the event
+func (t *Topic[E]) Publish(event E) {
+	for _, s := range t.handlers {
+		s.handler(event)
+	}
+}
+
+// EventBus carries gameplay events from the systems that cause them to anything
+// that reacts to them (audio, popups, the HUD, stats), so neither side needs to
+// know about the other. One bus lives for the whole game; managers that are
+// recreated on a restart publish to the same bus, so subscriptions survive.
+type EventBus struct {
+	CreepSpawned  Topic[CreepSpawned]
+	CreepDamaged  Topic[CreepDamaged]
+	CreepKilled   Topic[CreepKilled]
+	CreepEscaped  Topic[CreepEscaped]
+	TowerBuilt    Topic[TowerBuilt]
+	TowerFired    Topic[TowerFired]
+	ProjectileHit Topic[ProjectileHit]
+	WaveStarted   Topic[WaveStarted]
+	WaveCleared   Topic[WaveCleared]
+	GoldChanged   Topic[GoldChanged]
+}
+
+// NewEventBus creates a bus with no subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Positions in events are in tile coordinates. Creep positions are the top-left
+// of the creep's sprite, as in Creep.X and Creep.Y.
+
+// CreepSpawned is published when a creep is added to the map
+type CreepSpawned struct {
+	CreepID int
+	X, Y    float64
+}
+
+// CreepDamaged is published when a projectile takes health off a creep
+type CreepDamaged struct {
+	CreepID       int
+	X, Y          float64
+	Damage        float64 // Health actually removed
+	SourceTowerID int     // PlacedTower.ID of the tower that fired
+	Killed        bool    // This hit finished the creep off
+}
+
+// CreepKilled is published when a creep dies and pays out its reward
+type CreepKilled struct {
+	CreepID    int
+	X, Y       float64
+	GoldReward int
+}
+
+// CreepEscaped is published when a creep walks off the map
+type CreepEscaped struct {
+	CreepID int
+	X, Y    float64
+	Damage  float64 // Health the player loses
+}
+
+// TowerBuilt is published when a tower finishes building
+type TowerBuilt struct {
+	ID      int // PlacedTower.ID
+	TowerID int // Tower type
+	X, Y    int // Tile
+}
+
+// TowerFired is published when a tower shoots
+type TowerFired struct {
+	ID      int // PlacedTower.ID
+	TowerID int // Tower type
+}
+
+// ProjectileHit is published when a projectile stops, whether on a creep or at the end of its range
+type ProjectileHit struct {
+	X, Y           float64
+	ProjectileType int // The tower type that fired it
+	HitCreep       bool
+}
+
+// WaveStarted is published when a wave of creeps is sent in
+type WaveStarted struct {
+	Wave   int // Counting from 1
+	Creeps int
+}
+
+// WaveCleared is published when the last creep of a wave has died or escaped
+type WaveCleared struct {
+	Wave int
+}
+
+// GoldChanged is published whenever the player's gold changes
+type GoldChanged struct {
+	Gold  int // New total
+	Delta int
+}
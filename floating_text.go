@@ -0,0 +1,176 @@
+package main
+
+import (
+	"image/color"
+	"math/rand"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Floating text tuning
+const (
+	maxFloatingTexts     = 128  // Pool size; when full the oldest text is reused
+	floatingTextLifetime = 0.9  // Seconds a text stays on screen
+	floatingTextRise     = 0.8  // Tiles a text drifts upwards over its lifetime
+	floatingTextJitter   = 0.25 // Random sideways offset in tiles, so hits in a row don't stack
+	floatingTextFontSize = 22.0 // In UI pixels
+)
+
+// Floating text colours
+var (
+	damageTextColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	goldTextColor   = color.RGBA{R: 255, G: 215, B: 80, A: 255}
+	healthTextColor = color.RGBA{R: 255, G: 70, B: 70, A: 255}
+)
+
+// FloatingText is a short piece of text, such as a damage number, that rises and
+// fades out above a point on the map
+type FloatingText struct {
+	X, Y   float64 // Starting position in tile coordinates
+	Text   string
+	Color  color.RGBA
+	Age    float64 // Seconds since it was spawned
+	Active bool
+}
+
+// floatingTextKey identifies a cached number string such as "+15"
+type floatingTextKey struct {
+	prefix byte
+	value  int
+}
+
+// FloatingTextManager owns a fixed pool of floating texts, so spawning and
+// drawing them doesn't allocate once the pool and caches are warm
+type FloatingTextManager struct {
+	texts   [maxFloatingTexts]FloatingText
+	next    int                        // Slot the next text goes in
+	strings map[floatingTextKey]string // Formatted numbers, reused between texts
+	face    *text.GoTextFace           // Font face for the last UI scale drawn at
+	cols    int                        // Map size in tiles; texts are kept on the map
+	rows    int
+}
+
+// NewFloatingTextManager creates an empty pool
+func NewFloatingTextManager() *FloatingTextManager {
+	return &FloatingTextManager{strings: make(map[floatingTextKey]string)}
+}
+
+// Subscribe shows damage numbers for hits, gold for kills and health lost for escapes
+func (fm *FloatingTextManager) Subscribe(events *EventBus) {
+	// Creep positions are the top-left of the sprite, so centre the text over it
+	events.CreepDamaged.Subscribe(func(e CreepDamaged) { fm.SpawnDamage(e.X+0.5, e.Y, e.Damage) })
+	events.CreepKilled.Subscribe(func(e CreepKilled) { fm.SpawnGold(e.X+0.5, e.Y, e.GoldReward) })
+	events.CreepEscaped.Subscribe(func(e CreepEscaped) { fm.SpawnHealthLoss(e.X+0.5, e.Y, int(e.Damage)) })
+}
+
+// SetMapSize keeps texts inside a map of the given size in tiles. Creeps escape
+// just off the map, and their popups would be lost otherwise.
+func (fm *FloatingTextManager) SetMapSize(cols, rows int) {
+	fm.cols, fm.rows = cols, rows
+}
+
+// SpawnDamage shows the damage a hit did at the impact point
+func (fm *FloatingTextManager) SpawnDamage(x, y, damage float64) {
+	if damage < 0.5 {
+		return // Rounds to nothing; not worth showing
+	}
+	fm.spawn(x, y, fm.number(0, int(damage+0.5)), damageTextColor)
+}
+
+// SpawnGold shows gold earned at a point, such as a killed creep
+func (fm *FloatingTextManager) SpawnGold(x, y float64, gold int) {
+	fm.spawn(x, y, fm.number('+', gold), goldTextColor)
+}
+
+// SpawnHealthLoss shows health lost at a point, such as where a creep escaped
+func (fm *FloatingTextManager) SpawnHealthLoss(x, y float64, health int) {
+	fm.spawn(x, y, fm.number('-', health), healthTextColor)
+}
+
+// Clear removes every text
+func (fm *FloatingTextManager) Clear() {
+	for i := range fm.texts {
+		fm.texts[i].Active = false
+	}
+}
+
+// number returns value formatted with an optional sign prefix, from the cache when possible
+func (fm *FloatingTextManager) number(prefix byte, value int) string {
+	key := floatingTextKey{prefix: prefix, value: value}
+	if s, ok := fm.strings[key]; ok {
+		return s
+	}
+	s := strconv.Itoa(value)
+	if prefix != 0 {
+		s = string(prefix) + s
+	}
+	fm.strings[key] = s
+	return s
+}
+
+// spawn puts a text in the next slot; the slots are used round-robin, so when
+// every slot is busy the oldest text makes way
+func (fm *FloatingTextManager) spawn(x, y float64, s string, clr color.RGBA) {
+	if fm.cols > 0 && fm.rows > 0 {
+		x = min(max(x, 0.5), float64(fm.cols)-0.5)
+		y = min(max(y, 1), float64(fm.rows)-0.5)
+	}
+	fm.texts[fm.next] = FloatingText{
+		X:      x + (rand.Float64()*2-1)*floatingTextJitter,
+		Y:      y,
+		Text:   s,
+		Color:  clr,
+		Active: true,
+	}
+	fm.next = (fm.next + 1) % maxFloatingTexts
+}
+
+// Update ages the texts and retires those that have finished
+func (fm *FloatingTextManager) Update(deltaTime float64) {
+	for i := range fm.texts {
+		ft := &fm.texts[i]
+		if !ft.Active {
+			continue
+		}
+		ft.Age += deltaTime
+		if ft.Age >= floatingTextLifetime {
+			ft.Active = false
+		}
+	}
+}
+
+// Draw renders the texts over the map. They follow the camera but keep the same
+// size on screen whatever the zoom, so they stay readable.
+func (fm *FloatingTextManager) Draw(screen *ebiten.Image, params RenderParams) {
+	size := max(floatingTextFontSize*params.UIScale, 8)
+	if fm.face == nil || fm.face.Size != size {
+		fm.face = uiFont(nil, floatingTextFontSize, params.UIScale)
+	}
+
+	var opts text.DrawOptions
+	for i := range fm.texts {
+		ft := &fm.texts[i]
+		if !ft.Active {
+			continue
+		}
+		progress := ft.Age / floatingTextLifetime
+
+		// Ease out as it rises, and fade over the second half of its life
+		rise := floatingTextRise * (1 - (1-progress)*(1-progress))
+		alpha := 1.0
+		if progress > 0.5 {
+			alpha = 1 - (progress-0.5)*2
+		}
+
+		screenX, screenY := params.View.TileToScreen(ft.X, ft.Y-rise)
+		opts.GeoM.Reset()
+		opts.GeoM.Translate(screenX, screenY-fm.face.Size)
+		opts.PrimaryAlign = text.AlignCenter
+		opts.ColorScale.Reset()
+		opts.ColorScale.ScaleWithColor(ft.Color)
+		opts.ColorScale.ScaleAlpha(float32(alpha))
+		text.Draw(screen, ft.Text, fm.face, &opts)
+	}
+}
@@ -1,224 +1,267 @@
 package main
 
 import (
-	"fmt"
-	"math/rand"
-	"time"
-
-	stopwatch "github.com/RAshkettle/Stopwatch"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
+// gameSpeeds are the simulation speeds the player can pick. At speed n the
+// simulation takes n fixed steps per tick, so creeps, towers, projectiles and the
+// spawn and gold stopwatches all speed up together.
+var gameSpeeds = []int{1, 2, 4}
+
 type GameScene struct {
 	sceneManager *SceneManager
-	level        *TilemapJSON
-	images       TileImageMap
-
-	creepManager  *CreepManager
-	lastUpdate    time.Time
-	renderer      *Renderer
-	playerHealth  int
-	maxHealth     int
-	spawnTimer    *stopwatch.Stopwatch
-	hasSpawned    bool // Flag to prevent multiple spawns
-	uiManager     *UIManager
-	towerManager  *TowerManager
-	currentGold   int
-	goldTimer     *stopwatch.Stopwatch
-	selectedTower int
+	config       *Config
+	session      *GameSession // The current run; replaced on restart, level change and load
+	renderer     *Renderer
+	mapCache     *MapCache
+	screen       Screen               // Screen from the last layout
+	pauseScene   *PauseScene          // Pushed over the game to pause it
+	floatingText *FloatingTextManager // Damage numbers and gold and health popups
+	debug        *DebugOverlay
+	console      *Console  // Developer console with cheat commands, toggled with backtick
+	events       *EventBus // Gameplay events; lives as long as the scene
+
+	// Widgets over the map and the tray
+	ui          *UI
+	hud         *GameHUD
+	towerPanel  *TowerPanel
+	tray        *TowerTray
+	mapLayer    *Panel // HUD and tower panel, laid out over the fitted map
+	mouseOverUI bool   // The cursor was over a widget on the last update
 }
 
 func (g *GameScene) Draw(screen *ebiten.Image) {
+	s := g.session
+	params := g.renderer.CalculateRenderParams(g.screen.Width, g.screen.Height, s.level, s.camera)
 
-	if g.level == nil {
-		return
-	}
-
-	const tileSize = 64 // Standard tile size
-
-	// Use the same logical screen size for both input and rendering to ensure consistency
-	dummyImageForParams := ebiten.NewImage(1920, 1280) // Use the same dimensions as Layout()
-	params := g.renderer.CalculateRenderParams(dummyImageForParams, g.level)
-
-	// Draw layers in reverse order (last layer first)
-	//for i := len(g.level.Layers) - 1; i >= 0; i-- {
-	for i := 0; i < len(g.level.Layers); i++ {
-
-		layer := g.level.Layers[i]
+	// Static tile layers come from the pre-rendered cache
+	g.mapCache.Draw(screen, params, s.level, s.images)
+	s.towerManager.DrawInspectedRange(screen, params)
 
-		// Draw each tile in the layer
-		for y := 0; y < layer.Height; y++ {
-			for x := 0; x < layer.Width; x++ {
-				index := y*layer.Width + x
-				if index < len(layer.Data) {
-					tileID := layer.Data[index]
-
-					// Skip empty tiles (ID 0)
-					if tileID == 0 {
-						continue
-					}
-
-					// Get the tile image from the map
-					if tileImage, exists := g.images.Images[tileID]; exists && tileImage != nil {
-						// Calculate screen position using render params
-						screenX := float64(x * tileSize)
-						screenY := float64(y * tileSize)
-
-						// Draw the tile with proper scaling and offset
-						opts := &ebiten.DrawImageOptions{}
-						opts.GeoM.Scale(params.Scale, params.Scale)
-						opts.GeoM.Translate(screenX*params.Scale+params.OffsetX, screenY*params.Scale+params.OffsetY)
-						screen.DrawImage(tileImage, opts)
-					}
-				}
-			}
-		}
+	// World objects follow the camera
+	s.creepManager.Draw(screen, params)
+	if g.config.ShowHealthBars {
+		s.creepManager.DrawHealthBars(screen, params)
 	}
+	s.towerManager.DrawPlacedTowers(screen, params)
+	s.towerManager.DrawBuildingAnimations(screen, params)
+	s.towerManager.DrawProjectiles(screen, params)
+	g.floatingText.Draw(screen, params)
+	g.debug.Draw(screen, params, s)
+
+	// The tray and HUD are drawn over the world so a zoomed-in map slides underneath them
+	g.ui.Draw(screen)
+
+	// The placement ghost stands in for the cursor, but not over the UI, the console or the pause menu
+	placing := s.selectedTower
+	if g.mouseOverUI || g.console.Open || g.sceneManager.Top() != g {
+		placing = 0
+	}
+	s.towerManager.DrawPlacementIndicator(screen, params, placing, s.level)
 
-	g.towerManager.DrawTowerTray(screen, params, g.selectedTower, g.uiManager)
-	g.creepManager.Draw(screen, params)
-	g.towerManager.DrawPlacedTowers(screen, params, g.level)
-	g.uiManager.DrawHealthBar(screen, params, g.playerHealth, g.maxHealth)
-	g.uiManager.DrawGoldDisplay(screen, params, g.currentGold)
-	g.towerManager.DrawBuildingAnimations(screen, params, g.level)
-	g.towerManager.DrawPlacementIndicator(screen, params, g.selectedTower, g.level)
-	g.towerManager.DrawProjectiles(screen, params, g.level)
+	g.console.Draw(screen, g.screen.Scale)
 }
 
 func (g *GameScene) Update() error {
+	s := g.session
+
 	// Check if player health has reached zero - game over!
-	if g.playerHealth <= 0 {
+	if s.Over() {
+		g.sceneManager.audio.Play(SoundGameOver)
 		g.sceneManager.TransitionTo(SceneEndScreen)
 		return nil
 	}
 
-	// Calculate delta time
-	now := time.Now()
-	deltaTime := float64(0) / float64(ebiten.TPS())
+	// Ebiten calls Update a fixed number of times a second, so each tick is one fixed step
+	deltaTime := 1 / float64(ebiten.TPS())
 
-	if !g.lastUpdate.IsZero() {
-		deltaTime = now.Sub(g.lastUpdate).Seconds()
-	}
-	g.lastUpdate = now
+	g.renderer.SetUIScale(g.config.UIScale)
 
-	g.creepManager.Update(g.level, deltaTime)
-	g.towerManager.UpdateBuildingAnimations(deltaTime)
-	// Update the spawn timer
-	g.spawnTimer.Update()
+	// While the console is open it has the keyboard, and the mouse is left alone too
+	consoleOpen := g.console.Update()
+	if !consoleOpen {
+		if g.pauseRequested() {
+			g.sceneManager.Push(g.pauseScene, overlayTransition)
+			return nil
+		}
+		if g.config.Keys.JustPressed(ActionCycleSpeed) {
+			s.cycleSpeed()
+		}
+		if g.config.Keys.JustPressed(ActionDebugOverlay) {
+			g.debug.Toggle()
+		}
+	}
 
-	// Check if timer is done to spawn new wave
-	if g.spawnTimer.IsDone() && !g.hasSpawned {
-		g.spawnNewWave()
-		g.spawnTimer.Stop()
-		g.hasSpawned = true
+	for step := 0; step < s.speed && !s.Over(); step++ {
+		s.Step(deltaTime)
 	}
+	// Popups age in real time so they stay readable at 4x
+	g.floatingText.Update(deltaTime)
 
-	// Check if all creeps are removed and timer is not already running
-	if len(g.creepManager.creeps) == 0 && !g.spawnTimer.IsRunning() && g.hasSpawned {
-		g.spawnTimer.Start()
-		g.hasSpawned = false
+	if consoleOpen {
+		g.layoutUI(g.renderer.CalculateRenderParams(g.screen.Width, g.screen.Height, s.level, s.camera))
+		return nil
 	}
 
-	g.goldTimer.Update()
-	if g.goldTimer.IsDone() {
-		g.currentGold++
-		g.goldTimer.Reset()
+	// The focus key (C by default) glides the view to the tower in the info panel
+	if tower := s.towerManager.InspectedTower(); tower != nil && g.config.Keys.JustPressed(ActionFocusTower) {
+		s.camera.Follow((float64(tower.X)+0.5)*float64(s.level.TileWidth), (float64(tower.Y)+0.5)*float64(s.level.TileHeight))
 	}
 
 	// Handle tower selection input (pass current gold for cost checking)
-	// Use the logical screen size from Layout() instead of actual window size
-	dummyImageForParams := ebiten.NewImage(1920, 1280) // Use the same dimensions as Layout()
-	inputParams := g.renderer.CalculateRenderParams(dummyImageForParams, g.level)
-
-	// Handle tower selection input
-	if clicked, towerIndex := g.towerManager.HandleTowerSelection(g.level, g.currentGold, inputParams); clicked {
-		g.selectedTower = towerIndex
+	// Use the screen size from Layout() instead of actual window size
+	s.camera.Update(deltaTime, g.renderer.Viewport(g.screen.Width, g.screen.Height, s.level))
+	inputParams := g.renderer.CalculateRenderParams(g.screen.Width, g.screen.Height, s.level, s.camera)
+
+	// Widgets get the mouse first, laid out as they were last drawn
+	g.mouseOverUI = g.ui.Update(inputParams.UIScale)
+	g.tray.HandleInput(s.currentGold)
+
+	// Then the map, unless the cursor is over the UI
+	s.towerManager.HandleTowerInspection(s.selectedTower, inputParams, g.mouseOverUI)
+	if !g.mouseOverUI {
+		gold := s.currentGold
+		s.towerManager.HandleTowerPlacement(s.selectedTower, s.level, inputParams, &gold)
+		s.towerManager.HandleTowerSelling(s.level, inputParams, &gold)
+		s.addGold(gold - s.currentGold)
 	}
-	g.towerManager.HandleTowerPlacement(g.selectedTower, g.level, inputParams, &g.currentGold)
-	g.towerManager.UpdatePlacedTowers(deltaTime, g.creepManager.creeps)
 
+	g.layoutUI(inputParams)
 	return nil
 }
 
-func (t *GameScene) Layout(outerWidth, outerHeight int) (int, int) {
-	return 1920, 1280
+// pauseRequested reports whether the player asked to pause this tick. The pause
+// key (P by default) always pauses; Escape only does when it isn't cancelling
+// tower placement or closing the tower panel.
+func (g *GameScene) pauseRequested() bool {
+	if g.config.Keys.JustPressed(ActionPause) {
+		return true
+	}
+	return inpututil.IsKeyJustPressed(ebiten.KeyEscape) &&
+		g.session.selectedTower == 0 && g.session.towerManager.InspectedTower() == nil
+}
+
+// OnEnter starts the game music
+func (g *GameScene) OnEnter() {
+	g.sceneManager.audio.PlayMusic(MusicGame)
 }
 
-func NewGameScene(sm *SceneManager) *GameScene {
-	t, err := NewTilemapJSON("map/level.tmj")
+// startSession replaces the current run with a fresh one on levelPath, restored
+// from save when one is given. Restart, level changes and loading all come through
+// here. The old session is only closed once the new level has loaded, so a bad
+// level leaves the current game untouched.
+func (g *GameScene) startSession(levelPath string, save *SaveGame) error {
+	session, err := NewGameSession(levelPath, g.events, g.config)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if save != nil {
+		session.restore(*save)
+	}
+	if g.session != nil {
+		g.session.Close()
 	}
-	g := &GameScene{
-		sceneManager: sm,
-		lastUpdate:   time.Now(),                              // Initialize the timer
-		creepManager: NewCreepManager(),                       // Initialize the creep manager
-		spawnTimer:   stopwatch.NewStopwatch(5 * time.Second), // 5 second timer
-		hasSpawned:   true,                                    // Start as true since we spawn initially
-		maxHealth:    100,
-		playerHealth: 100,
-		uiManager:    NewUIManager(), // Initialize the UI manager
-		renderer:     NewRenderer(),  // Initialize the renderer
-		towerManager: NewTowerManager(),
-	}
-	g.level = t
-	g.images = t.LoadTiles()
-	g.creepManager.SetOnCreepEscape(func(damage float64) {
-		g.playerHealth -= int(damage)
-		if g.playerHealth < 0 {
-			g.playerHealth = 0
-		}
-	})
-	g.creepManager.SetOnCreepKilled(func(goldReward int) {
-		g.currentGold += goldReward
-	})
-	g.currentGold = 350
-	g.goldTimer = stopwatch.NewStopwatch(2 * time.Second)
-	g.goldTimer.Start()
-	g.creepManager.SetOnCreepKilled(func(goldReward int) {
-		g.currentGold += goldReward
-	})
 
-	g.spawnNewWave()
-	return g
+	g.session = session
+	g.floatingText.Clear()
+	g.floatingText.SetMapSize(session.level.MapSize())
+	session.Start()
+	return nil
 }
 
-// spawnNewWave spawns a new wave of creeps with randomized count
-func (g *GameScene) spawnNewWave() {
-	pathNodes := g.level.GetWaypoints()
-	if len(pathNodes) == 0 {
-		fmt.Println("Warning: No waypoints found for spawning creeps")
-		return
-	}
+// Restart starts the current level again from scratch
+func (g *GameScene) Restart() error {
+	return g.startSession(g.session.levelPath, nil)
+}
 
-	// Spawn firebugs at the first waypoint (waypoint 0)
-	startX := float64(pathNodes[0].X)
-	startY := float64(pathNodes[0].Y)
+// StartLevel starts a fresh run on another level
+func (g *GameScene) StartLevel(levelPath string) error {
+	return g.startSession(levelPath, nil)
+}
 
-	// Randomize creep count between 5-10
-	creepCount := 5 + rand.Intn(6) // 5 + (0-5) = 5-10
+// Save writes the current run to the save slot
+func (g *GameScene) Save() error {
+	return WriteSaveGame(g.session.SaveGame())
+}
 
-	SpawnCreeps(g.creepManager, creepCount, startX, startY, pathNodes)
+// Load replaces the current run with the one in the save slot
+func (g *GameScene) Load() error {
+	save, err := ReadSaveGame()
+	if err != nil {
+		return err
+	}
+	if save.Level == "" {
+		save.Level = defaultLevel // Saved before levels were recorded
+	}
+	return g.startSession(save.Level, &save)
+}
 
+// layoutUI brings the widgets up to date with the game and lays them out for drawing
+func (g *GameScene) layoutUI(params RenderParams) {
+	s := g.session
+	g.hud.Sync(s.playerHealth, s.maxHealth, s.currentGold, s.speed, g.config.Keys[ActionCycleSpeed])
+	g.towerPanel.Sync(s.towerManager.InspectedTower(), g.config.Keys[ActionCycleTargeting])
+	g.tray.Sync(s.currentGold, s.selectedTower)
+
+	// The HUD and tower panel sit over the map as it is fitted, so they don't move with the camera
+	mapArea := UIRect{
+		X: params.UIOffsetX,
+		Y: params.UIOffsetY,
+		W: params.TrayX - params.UIOffsetX,
+		H: float64(params.ScreenHeight) - 2*params.UIOffsetY,
+	}
+	g.mapLayer.Arrange(mapArea, params.UIScale)
+	g.tray.Layout(params)
 }
 
-// Reset resets the game scene to initial state
-func (g *GameScene) Reset() {
-	g.playerHealth = g.maxHealth
+func (g *GameScene) Layout(screen Screen) {
+	g.screen = screen
+	g.renderer.SetScreenScale(screen.Scale)
+}
 
-	// Reset components
-	g.creepManager = NewCreepManager()
-	g.creepManager.SetOnCreepEscape(func(damage float64) {
-		g.playerHealth -= int(damage)
-		if g.playerHealth < 0 {
-			g.playerHealth = 0
-		}
-	})
-	// Spawn first wave
-	if g.level != nil {
-		pathNodes := g.level.GetWaypoints()
-		if len(pathNodes) > 0 {
-			g.spawnNewWave()
-		}
+func NewGameScene(sm *SceneManager) (*GameScene, error) {
+	g := &GameScene{
+		sceneManager: sm,
+		config:       sm.config,
+		screen:       virtualScreen,
+		events:       NewEventBus(),
+		renderer:     NewRenderer(), // Initialize the renderer
+		mapCache:     NewMapCache(),
+		floatingText: NewFloatingTextManager(),
+		debug:        NewDebugOverlay(),
+		console:      NewConsole(),
+	}
+	g.renderer.SetUIScale(sm.config.UIScale)
+	g.floatingText.Subscribe(g.events)
+
+	if err := g.startSession(defaultLevel, nil); err != nil {
+		return nil, err
 	}
+	g.buildUI()
+	g.registerConsoleCommands()
+	return g, nil
+}
+
+// buildUI creates the HUD, tower panel and tray widgets and the pause menu. The widgets
+// outlive sessions, so their callbacks look the current session up when they run.
+func (g *GameScene) buildUI() {
+	g.hud = NewGameHUD(func(speed int) {
+		g.session.setSpeed(speed)
+	})
+	g.towerPanel = NewTowerPanel(func() {
+		g.session.towerManager.CycleTargeting()
+	})
+	g.tray = NewTowerTray(func(towerID int) *ebiten.Image {
+		return g.session.towerManager.TrayIcon(towerID)
+	}, func(towerID int) {
+		g.session.selectedTower = towerID
+	})
+
+	g.mapLayer = NewPanel(LayoutStack)
+	g.mapLayer.Add(g.hud.Root, g.towerPanel.Root)
+	g.ui = NewUI(g.mapLayer, g.tray.Root)
+	g.layoutUI(g.renderer.CalculateRenderParams(g.screen.Width, g.screen.Height, g.session.level, nil))
+	g.pauseScene = NewPauseScene(g.sceneManager, g)
 }
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+	"towerDefense/tiled"
+
+	stopwatch "github.com/RAshkettle/Stopwatch"
+)
+
+// defaultLevel is the map a new game starts on
+const defaultLevel = "map/level.tmj"
+
+const (
+	startingHealth = 100
+	startingGold   = 350
+)
+
+// GameSession holds everything that belongs to one run of one level: the map,
+// the camera, creeps and towers, the player's health and gold, the wave and gold
+// timers and the tower being placed. A new session is built for every new game,
+// restart, level change and loaded save, so nothing from one run leaks into the
+// next. The event bus belongs to the scene and outlives sessions; a session's own
+// subscriptions are removed by Close.
+type GameSession struct {
+	levelPath string
+	level     *tiled.TilemapJSON
+	images    TileImageMap
+	camera    *Camera
+
+	creepManager *CreepManager
+	towerManager *TowerManager
+	events       *EventBus
+	unsubscribe  []func() // Removes the session's event handlers
+
+	playerHealth  int
+	maxHealth     int
+	currentGold   int
+	spawnTimer    *stopwatch.Stopwatch
+	hasSpawned    bool // Flag to prevent multiple spawns
+	goldTimer     *stopwatch.Stopwatch
+	wave          int  // Number of the current wave, counting from 1
+	selectedTower int  // Tower being placed from the tray, 0 for none
+	speed         int  // Simulation steps per tick, one of gameSpeeds unless set from the console
+	god           bool // Escaping creeps do no damage; set from the console
+}
+
+// NewGameSession loads a level and sets up a fresh run on it. Call Start to send
+// the first wave.
+func NewGameSession(levelPath string, events *EventBus, config *Config) (*GameSession, error) {
+	level, err := NewTilemapJSON(levelPath)
+	if err != nil {
+		return nil, err
+	}
+	images, err := LoadTiles(level)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &GameSession{
+		levelPath:    levelPath,
+		level:        level,
+		images:       images,
+		camera:       NewCamera(),
+		creepManager: NewCreepManager(events),
+		towerManager: NewTowerManager(events),
+		events:       events,
+		playerHealth: startingHealth,
+		maxHealth:    startingHealth,
+		currentGold:  startingGold,
+		spawnTimer:   stopwatch.NewStopwatch(5 * time.Second), // 5 second timer
+		hasSpawned:   true,                                    // Start as true since we spawn initially
+		goldTimer:    stopwatch.NewStopwatch(2 * time.Second),
+		speed:        config.DefaultSpeed,
+	}
+	s.towerManager.SetPlacementValidator(s.canPlaceTower)
+	s.towerManager.SetOnTowersChanged(s.onTowersChanged)
+	s.towerManager.SetKeyBindings(config.Keys)
+	s.camera.SetKeyBindings(config.Keys)
+
+	s.unsubscribe = append(s.unsubscribe,
+		events.CreepEscaped.Subscribe(s.onCreepEscaped),
+		events.CreepKilled.Subscribe(s.onCreepKilled),
+	)
+	return s, nil
+}
+
+// Start sends the first wave and starts the gold timer
+func (s *GameSession) Start() {
+	s.goldTimer.Start()
+	s.spawnNewWave()
+}
+
+// Close removes the session's event handlers so a finished run stops reacting to
+// the events of the next one
+func (s *GameSession) Close() {
+	for _, unsubscribe := range s.unsubscribe {
+		unsubscribe()
+	}
+	s.unsubscribe = nil
+	s.towerManager.Close()
+}
+
+// Over reports whether the player has run out of health
+func (s *GameSession) Over() bool {
+	return s.playerHealth <= 0
+}
+
+// Step advances creeps, towers, projectiles and the wave and gold timers by one step
+func (s *GameSession) Step(deltaTime float64) {
+	s.creepManager.Update(s.level, deltaTime)
+	s.towerManager.UpdateBuildingAnimations(deltaTime)
+	// Update the spawn timer
+	s.spawnTimer.Update()
+
+	// Check if timer is done to spawn new wave
+	if s.spawnTimer.IsDone() && !s.hasSpawned {
+		s.spawnNewWave()
+		s.spawnTimer.Stop()
+		s.hasSpawned = true
+	}
+
+	// Check if all creeps are removed and timer is not already running
+	if len(s.creepManager.creeps) == 0 && !s.spawnTimer.IsRunning() && s.hasSpawned {
+		s.spawnTimer.Start()
+		s.hasSpawned = false
+		s.events.WaveCleared.Publish(WaveCleared{Wave: s.wave})
+	}
+
+	s.goldTimer.Update()
+	if s.goldTimer.IsDone() {
+		s.addGold(1)
+		s.goldTimer.Reset()
+	}
+
+	s.towerManager.UpdatePlacedTowers(deltaTime, s.creepManager.Grid())
+}
+
+// spawnNewWave spawns a new wave of creeps with randomized count
+func (s *GameSession) spawnNewWave() {
+	pathNodes := s.creepPath()
+	if len(pathNodes) == 0 {
+		fmt.Println("Warning: No path found for spawning creeps")
+		return
+	}
+
+	// Spawn firebugs at the first waypoint (waypoint 0)
+	startX := float64(pathNodes[0].X)
+	startY := float64(pathNodes[0].Y)
+
+	// Randomize creep count between 5-10
+	creepCount := 5 + rand.Intn(6) // 5 + (0-5) = 5-10
+
+	SpawnCreeps(s.creepManager, creepCount, startX, startY, pathNodes)
+	s.wave++
+	s.events.WaveStarted.Publish(WaveStarted{Wave: s.wave, Creeps: creepCount})
+}
+
+// spawnCreeps sends extra creeps down the current path outside the wave schedule.
+// It reports false when there is no path to send them down.
+func (s *GameSession) spawnCreeps(count int) bool {
+	pathNodes := s.creepPath()
+	if len(pathNodes) == 0 {
+		return false
+	}
+	SpawnCreeps(s.creepManager, count, float64(pathNodes[0].X), float64(pathNodes[0].Y), pathNodes)
+	return true
+}
+
+// jumpToWave sends the given wave now, without waiting for the spawn timer
+func (s *GameSession) jumpToWave(wave int) {
+	s.wave = wave - 1
+	s.spawnNewWave()
+	s.spawnTimer.Stop()
+	s.hasSpawned = true
+}
+
+// killCreeps finishes off the live creep with the given ID, or every live creep
+// for an ID of 0, and returns how many it killed. They die the usual way, so the
+// kills still pay out.
+func (s *GameSession) killCreeps(id int) int {
+	killed := 0
+	for _, creep := range s.creepManager.creeps {
+		if !creep.IsActive() || creep.IsDying || (id != 0 && creep.ID != id) {
+			continue
+		}
+		creep.TakeDamage(creep.Health)
+		killed++
+	}
+	return killed
+}
+
+// setSpeed changes how many simulation steps run per tick
+func (s *GameSession) setSpeed(speed int) {
+	s.speed = speed
+}
+
+// cycleSpeed moves on to the next game speed, wrapping round to 1x
+func (s *GameSession) cycleSpeed() {
+	for i, speed := range gameSpeeds {
+		if speed == s.speed {
+			s.setSpeed(gameSpeeds[(i+1)%len(gameSpeeds)])
+			return
+		}
+	}
+	s.setSpeed(gameSpeeds[0])
+}
+
+// onCreepEscaped takes the creep's damage off the player's health
+func (s *GameSession) onCreepEscaped(e CreepEscaped) {
+	if s.god {
+		return
+	}
+	s.playerHealth -= int(e.Damage)
+	if s.playerHealth < 0 {
+		s.playerHealth = 0
+	}
+}
+
+// onCreepKilled pays out the creep's gold reward
+func (s *GameSession) onCreepKilled(e CreepKilled) {
+	s.addGold(e.GoldReward)
+}
+
+// addGold changes the player's gold and announces it
+func (s *GameSession) addGold(delta int) {
+	if delta == 0 {
+		return
+	}
+	s.currentGold += delta
+	s.events.GoldChanged.Publish(GoldChanged{Gold: s.currentGold, Delta: delta})
+}
+
+// SaveGame captures the session for the save slot
+func (s *GameSession) SaveGame() SaveGame {
+	return SaveGame{
+		Level:  s.levelPath,
+		Health: s.playerHealth,
+		Gold:   s.currentGold,
+		Towers: s.towerManager.SavedTowers(),
+	}
+}
+
+// restore puts a saved game's health, gold and towers into a session that hasn't started yet
+func (s *GameSession) restore(save SaveGame) {
+	s.playerHealth = min(max(save.Health, 1), s.maxHealth)
+	s.currentGold = max(save.Gold, 0)
+	s.towerManager.RestoreTowers(save.Towers, s.level)
+}
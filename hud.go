@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// HUD layout, in UI pixels from the top-left corner of the map
+const (
+	hudMarginX       = 92.0  // Left edge of the labels
+	hudMarginY       = 60.0  // Top of the health row
+	hudLabelWidth    = 100.0 // Labels are padded to this so the bar lines up
+	hudRowSpacing    = 12.0
+	hudFontSize      = 20.0
+	hudSpeedSpacing  = 6.0 // Gap between the speed buttons
+	healthSegments   = 10
+	healthLowAt      = 50 // Health at or below this turns the bar orange
+	healthCriticalAt = 30 // and at or below this, bright red
+)
+
+// GameHUD shows the player's health and gold and the game speed buttons in the
+// top-left corner of the map
+type GameHUD struct {
+	Root         *Panel
+	healthBar    *ProgressBar
+	goldLabel    *Label
+	speedButtons []*Button  // One per entry in gameSpeeds
+	speedKey     ebiten.Key // Key named in the speed tooltips
+}
+
+// NewGameHUD builds the HUD widgets; onSpeed is called with the speed a speed button picks
+func NewGameHUD(onSpeed func(speed int)) *GameHUD {
+	healthLabel := NewLabel("Health:", hudFontSize)
+	healthLabel.Width = hudLabelWidth
+	healthBar := NewSegmentedBar(art.HealthLeft, art.HealthFill, art.HealthRight, healthSegments)
+
+	healthRow := NewPanel(LayoutHorizontal)
+	healthRow.Align = AlignCenter
+	healthRow.Add(healthLabel, healthBar)
+
+	goldLabel := NewLabel("", hudFontSize)
+
+	speedLabel := NewLabel("Speed:", hudFontSize)
+	speedLabel.Width = hudLabelWidth
+	speedRow := NewPanel(LayoutHorizontal)
+	speedRow.Align = AlignCenter
+	speedRow.Spacing = hudSpeedSpacing
+	speedRow.Add(speedLabel)
+	var speedButtons []*Button
+	for _, speed := range gameSpeeds {
+		button := NewButton(fmt.Sprintf("%dx", speed), func() { onSpeed(speed) })
+		speedButtons = append(speedButtons, button)
+		speedRow.Add(button)
+	}
+
+	root := NewPanel(LayoutVertical)
+	root.Spacing = hudRowSpacing
+	root.Anchor = AnchorTopLeft
+	root.OffsetX, root.OffsetY = hudMarginX, hudMarginY
+	root.Add(healthRow, goldLabel, speedRow)
+
+	h := &GameHUD{Root: root, healthBar: healthBar, goldLabel: goldLabel, speedButtons: speedButtons}
+	h.setSpeedKey(defaultKeyBindings()[ActionCycleSpeed])
+	return h
+}
+
+// setSpeedKey names the key that cycles the game speed in the speed button tooltips
+func (h *GameHUD) setSpeedKey(key ebiten.Key) {
+	h.speedKey = key
+	for i, button := range h.speedButtons {
+		button.Tooltip = &Tooltip{Lines: []string{
+			fmt.Sprintf("%dx speed", gameSpeeds[i]),
+			fmt.Sprintf("Press %s to cycle speeds", key),
+		}}
+	}
+}
+
+// Sync updates the HUD with the current health, gold, game speed and speed key
+func (h *GameHUD) Sync(currentHealth, maxHealth, currentGold, speed int, speedKey ebiten.Key) {
+	h.healthBar.Value = float64(currentHealth) / float64(maxHealth)
+
+	// Tint the bar as health runs low
+	var tint ebiten.ColorScale
+	if currentHealth <= healthCriticalAt {
+		tint.Scale(1.2, 0.3, 0.3, 1.0) // Bright red
+	} else if currentHealth <= healthLowAt {
+		tint.Scale(1.0, 0.6, 0.2, 1.0) // Orange
+	}
+	h.healthBar.FillTint = tint
+
+	h.goldLabel.Text = fmt.Sprintf("Gold: %d", currentGold)
+
+	for i, button := range h.speedButtons {
+		button.Selected = gameSpeeds[i] == speed
+	}
+	if speedKey != h.speedKey {
+		h.setSpeedKey(speedKey)
+	}
+}
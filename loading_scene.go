@@ -0,0 +1,113 @@
+package main
+
+import (
+	"image/color"
+	"time"
+	"towerDefense/assets"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// loadingBudget is how long the loading screen spends loading each tick, so the
+// window keeps redrawing and the progress bar moves
+const loadingBudget = 12 * time.Millisecond
+
+// Progress bar size, in UI pixels
+const (
+	loadingBarWidth  = 400.0
+	loadingBarHeight = 12.0
+)
+
+var (
+	loadingBarEmptyColor = color.RGBA{40, 45, 60, 255}
+	loadingBarFillColor  = color.RGBA{120, 160, 255, 255}
+)
+
+// LoadingScene runs the asset loading steps a few at a time, showing the file
+// being loaded and a progress bar. If a step fails it shows the error and a Quit
+// button instead of crashing, so a broken file in the override directory is
+// easy to track down.
+type LoadingScene struct {
+	sceneManager *SceneManager
+	loader       *assets.Loader
+	onDone       func() error // Called once every step has run
+	menu         *Menu
+	quitButton   *Button
+	failed       bool
+	quit         bool
+	screen       Screen
+}
+
+func (l *LoadingScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 15, 25, 255})
+	l.menu.Draw(screen)
+	if l.failed {
+		return
+	}
+
+	scale := l.screen.Scale * l.sceneManager.config.UIScale
+	w, h := loadingBarWidth*scale, loadingBarHeight*scale
+	x := (float64(l.screen.Width) - w) / 2
+	y := float64(l.screen.Height) * 0.75
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w), float32(h), loadingBarEmptyColor, false)
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(w*l.loader.Progress()), float32(h), loadingBarFillColor, false)
+}
+
+func (l *LoadingScene) Update() error {
+	if l.quit {
+		return ebiten.Termination
+	}
+	if l.failed {
+		l.menu.Update()
+		return nil
+	}
+
+	start := time.Now()
+	for !l.loader.Done() && time.Since(start) < loadingBudget {
+		if err := l.loader.Step(); err != nil {
+			l.fail(err)
+			return nil
+		}
+	}
+	if !l.loader.Done() {
+		l.menu.SetSubtitle(l.loader.Current())
+		return nil
+	}
+	if err := l.onDone(); err != nil {
+		l.fail(err)
+	}
+	return nil
+}
+
+// fail stops loading and shows the error
+func (l *LoadingScene) fail(err error) {
+	l.failed = true
+	l.menu.SetSubtitle(err.Error())
+	l.quitButton.Hidden = false
+	l.menu.FocusFirst()
+}
+
+func (l *LoadingScene) Layout(screen Screen) {
+	l.screen = screen
+	l.menu.Layout(screen, l.sceneManager.config.UIScale)
+}
+
+// NewLoadingScene creates a loading screen that runs steps in order and then
+// calls onDone
+func NewLoadingScene(sm *SceneManager, steps []assets.LoadStep, onDone func() error) *LoadingScene {
+	l := &LoadingScene{
+		sceneManager: sm,
+		loader:       assets.NewLoader(steps),
+		onDone:       onDone,
+		screen:       virtualScreen,
+	}
+	l.quitButton = NewButton("Quit", func() { l.quit = true })
+	l.quitButton.Hidden = true
+	l.menu = NewMenu(
+		"Loading", color.RGBA{220, 220, 255, 255},
+		l.loader.Current(), color.RGBA{180, 180, 200, 255},
+		l.quitButton,
+	)
+	return l
+}
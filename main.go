@@ -1,16 +1,33 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"towerDefense/assets"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+// gameAssets loads the game's art, sounds and maps
+var gameAssets *assets.AssetManager
+
 func main() {
-	sceneManager := NewSceneManager()
+	assetsDir := flag.String("assets", "", "directory of files to use in place of the built-in assets, by the same paths")
+	flag.Parse()
+	gameAssets = assets.NewAssetManager(*assetsDir)
+
+	config, err := LoadConfig()
+	if err != nil {
+		fmt.Println("Warning: using default settings:", err)
+	}
+
+	sceneManager := NewSceneManager(config, NewAudioManager(config))
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Towers of Defenders")
-	ebiten.SetWindowSize(1920, 1280)
+	config.ApplyDisplay()
+
+	err = ebiten.RunGame(sceneManager)
 
-	err := ebiten.RunGame(sceneManager)
 	if err != nil {
 		panic(err)
 	}
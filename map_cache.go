@@ -0,0 +1,93 @@
+package main
+
+import (
+	"towerDefense/tiled"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MapCache holds the static tile layers of a level pre-rendered into a single
+// offscreen image, so a frame costs one DrawImage instead of one per tile.
+// The image is baked at the map's native pixel size; resizing the window only
+// changes the transform used to draw it, so it is re-baked only when the tile
+// data changes (Invalidate) or a different level is drawn.
+type MapCache struct {
+	image *ebiten.Image
+	level *tiled.TilemapJSON // Level the image was baked from
+	dirty bool
+}
+
+// NewMapCache creates an empty cache; the first Draw bakes it
+func NewMapCache() *MapCache {
+	return &MapCache{dirty: true}
+}
+
+// Invalidate marks the cache stale so it is re-baked before the next draw.
+// Call it after changing any layer's tile data.
+func (mc *MapCache) Invalidate() {
+	mc.dirty = true
+}
+
+// Draw renders the cached map, baking it first if needed
+func (mc *MapCache) Draw(screen *ebiten.Image, params RenderParams, level *tiled.TilemapJSON, images TileImageMap) {
+	if level == nil {
+		return
+	}
+	if mc.dirty || mc.image == nil || mc.level != level {
+		mc.bake(level, images)
+	}
+
+	opts := &ebiten.DrawImageOptions{}
+	params.View.Apply(&opts.GeoM)
+	screen.DrawImage(mc.image, opts)
+}
+
+// bake draws every visible tile layer, first layer at the bottom, into the cache image
+func (mc *MapCache) bake(level *tiled.TilemapJSON, images TileImageMap) {
+	width, height := level.MapSize()
+	pixelWidth := max(width*level.TileWidth, 1)
+	pixelHeight := max(height*level.TileHeight, 1)
+
+	// Reuse the image when the size hasn't changed
+	if mc.image == nil || mc.image.Bounds().Dx() != pixelWidth || mc.image.Bounds().Dy() != pixelHeight {
+		if mc.image != nil {
+			mc.image.Deallocate()
+		}
+		mc.image = ebiten.NewImage(pixelWidth, pixelHeight)
+	} else {
+		mc.image.Clear()
+	}
+
+	for _, layer := range level.Layers {
+		// The buildable layer is gameplay data only, never drawn
+		if layer.Name == tiled.BuildableLayerName {
+			continue
+		}
+
+		// Draw each tile in the layer
+		for y := 0; y < layer.Height; y++ {
+			for x := 0; x < layer.Width; x++ {
+				index := y*layer.Width + x
+				if index >= len(layer.Data) {
+					continue
+				}
+				tileID := layer.Data[index]
+
+				// Skip empty tiles (ID 0)
+				if tileID == 0 {
+					continue
+				}
+
+				// Get the tile image from the map
+				if tileImage, exists := images.Images[tileID]; exists && tileImage != nil {
+					opts := &ebiten.DrawImageOptions{}
+					opts.GeoM.Translate(float64(x*level.TileWidth), float64(y*level.TileHeight))
+					mc.image.DrawImage(tileImage, opts)
+				}
+			}
+		}
+	}
+
+	mc.level = level
+	mc.dirty = false
+}
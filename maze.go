@@ -0,0 +1,73 @@
+package main
+
+import "towerDefense/tiled"
+
+// routeBlocked reports whether creeps can't walk a tile: blocked terrain or any tower, built or building
+func (s *GameSession) routeBlocked(col, row int) bool {
+	return s.level.IsBlocked(col, row) || s.towerManager.IsTowerAt(col, row)
+}
+
+// creepPath returns the route new creeps follow: the authored waypoints, or an A* route on maze levels
+func (s *GameSession) creepPath() []tiled.PathNode {
+	if !s.level.IsMazeMode() {
+		return s.level.GetWaypoints()
+	}
+
+	start, exit, ok := s.level.GetStartAndExit()
+	if !ok {
+		return nil
+	}
+	return tiled.FindPath(s.level, start, exit, s.routeBlocked)
+}
+
+// canPlaceTower rejects placements on maze levels that would seal off the exit,
+// either from the start or from any creep that is still walking its route.
+func (s *GameSession) canPlaceTower(col, row int) bool {
+	if !s.level.IsMazeMode() {
+		return true
+	}
+
+	start, exit, ok := s.level.GetStartAndExit()
+	if !ok {
+		return true
+	}
+
+	blocked := func(c, r int) bool {
+		return (c == col && r == row) || s.routeBlocked(c, r)
+	}
+
+	if tiled.FindPath(s.level, start, exit, blocked) == nil {
+		return false
+	}
+
+	for _, creep := range s.creepManager.creeps {
+		if !creep.IsActive() || creep.IsDying || creep.HasFinishedPath() {
+			continue
+		}
+		tile, next := creep.CurrentTile(), creep.NextTile()
+		if (tile.X == col && tile.Y == row) || (next.X == col && next.Y == row) {
+			return false // Can't build on top of a creep or in front of it
+		}
+		if tiled.FindPath(s.level, next, exit, blocked) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// onTowersChanged re-routes live creeps on maze levels after a tower is placed or sold
+func (s *GameSession) onTowersChanged() {
+	if !s.level.IsMazeMode() {
+		return
+	}
+
+	_, exit, ok := s.level.GetStartAndExit()
+	if !ok {
+		return
+	}
+
+	s.creepManager.RepathCreeps(func(from tiled.PathNode) []tiled.PathNode {
+		return tiled.FindPath(s.level, from, exit, s.routeBlocked)
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Menu layout, in UI pixels
+const (
+	menuTitleSize    = 48.0
+	menuSubtitleSize = 24.0
+	menuButtonWidth  = 260.0
+	menuButtonSize   = 24.0
+	menuSpacing      = 16.0
+)
+
+// Menu is a centred column of a title, a subtitle and buttons, as used by the
+// title and game over screens. The first button starts with keyboard focus and
+// the arrow keys, Tab, Enter and Space work as well as the mouse.
+type Menu struct {
+	ui       *UI
+	root     *Panel
+	subtitle *Label
+	buttons  []*Button
+	scale    float64
+}
+
+// NewMenu builds a menu
+func NewMenu(title string, titleColor color.Color, subtitle string, subtitleColor color.Color, buttons ...*Button) *Menu {
+	titleLabel := NewLabel(title, menuTitleSize)
+	titleLabel.Font = regularFontSource
+	titleLabel.Color = titleColor
+	subtitleLabel := NewLabel(subtitle, menuSubtitleSize)
+	subtitleLabel.Font = regularFontSource
+	subtitleLabel.Color = subtitleColor
+
+	column := NewPanel(LayoutVertical)
+	column.Anchor = AnchorCenter
+	column.Align = AlignCenter
+	column.Spacing = menuSpacing
+	column.Add(titleLabel, subtitleLabel)
+	for _, b := range buttons {
+		b.Width = menuButtonWidth
+		b.FontSize = menuButtonSize
+		column.Add(b)
+	}
+
+	root := NewPanel(LayoutStack)
+	root.Add(column)
+
+	m := &Menu{ui: NewUI(root), root: root, subtitle: subtitleLabel, buttons: buttons, scale: 1}
+	m.ui.ArrowKeys = true
+	m.FocusFirst()
+	return m
+}
+
+// FocusFirst gives keyboard focus back to the first button, e.g. when the menu reopens
+func (m *Menu) FocusFirst() {
+	if len(m.buttons) > 0 {
+		m.ui.Focus(m.buttons[0])
+	}
+}
+
+// SetSubtitle changes the line under the title, e.g. to report the result of a button
+func (m *Menu) SetSubtitle(subtitle string) {
+	m.subtitle.Text = subtitle
+}
+
+// Update handles input; call Layout first so the widgets know the screen size
+func (m *Menu) Update() {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible) // The game hides it while placing towers
+	m.ui.Update(m.scale)
+}
+
+// Draw renders the menu
+func (m *Menu) Draw(screen *ebiten.Image) {
+	m.ui.Draw(screen)
+}
+
+// Layout lays the menu out for the screen, scaled with it and then by the
+// player's UI scale setting
+func (m *Menu) Layout(screen Screen, uiScale float64) {
+	m.scale = screen.Scale * uiScale
+	m.root.Arrange(UIRect{W: float64(screen.Width), H: float64(screen.Height)}, m.scale)
+}
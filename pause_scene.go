@@ -0,0 +1,84 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// PauseScene is the pause menu. It is pushed over the game scene, which stops
+// updating underneath it but is still drawn.
+type PauseScene struct {
+	sceneManager *SceneManager
+	game         *GameScene
+	menu         *Menu
+}
+
+func (p *PauseScene) Draw(screen *ebiten.Image) {
+	p.menu.Draw(screen)
+}
+
+func (p *PauseScene) Update() error {
+	// Escape and the pause key resume; everything else goes to the menu
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || p.sceneManager.config.Keys.JustPressed(ActionPause) {
+		p.resume()
+		return nil
+	}
+	p.menu.Update()
+	return nil
+}
+
+func (p *PauseScene) Layout(screen Screen) {
+	p.menu.Layout(screen, p.sceneManager.config.UIScale)
+}
+
+// OnEnter clears the last status message and focuses Resume
+func (p *PauseScene) OnEnter() {
+	p.menu.SetSubtitle("")
+	p.menu.FocusFirst()
+}
+
+// Overlay keeps the paused game visible underneath the menu
+func (p *PauseScene) Overlay() bool {
+	return true
+}
+
+// resume closes the pause menu and carries on
+func (p *PauseScene) resume() {
+	p.sceneManager.Pop(overlayTransition)
+}
+
+func NewPauseScene(sm *SceneManager, game *GameScene) *PauseScene {
+	p := &PauseScene{sceneManager: sm, game: game}
+	p.menu = NewMenu(
+		"Paused", color.RGBA{220, 220, 255, 255},
+		"", color.RGBA{180, 180, 200, 255},
+		NewButton("Resume", p.resume),
+		NewButton("Restart", func() {
+			if err := game.Restart(); err != nil {
+				p.menu.SetSubtitle("Restart failed: " + err.Error())
+				return
+			}
+			p.resume()
+		}),
+		NewButton("Settings", sm.OpenSettings),
+		NewButton("Save", func() {
+			if err := game.Save(); err != nil {
+				p.menu.SetSubtitle("Save failed: " + err.Error())
+				return
+			}
+			p.menu.SetSubtitle("Game saved")
+		}),
+		NewButton("Quit to Title", func() {
+			// Abandon the run so Start on the title screen begins a new one
+			if err := game.Restart(); err != nil {
+				p.menu.SetSubtitle("Could not quit: " + err.Error())
+				return
+			}
+			sm.TransitionTo(SceneTitleScreen)
+		}),
+	)
+	p.menu.root.Background = color.RGBA{0, 0, 0, 160} // Dim the paused game
+	return p
+}
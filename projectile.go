@@ -2,7 +2,6 @@ package main
 
 import (
 	"math"
-	"towerDefense/assets"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -19,12 +18,18 @@ type Projectile struct {
 	TravelDistance  float64         // How far the projectile has traveled
 	MaxDistance     float64         // Maximum travel distance (5 tiles)
 	ProjectileType  int             // Type of projectile (based on tower type)
+	SourceTowerID   int             // Unique ID of the tower that fired it
+	Damage          float64         // Damage dealt on hit
+	SplashRadius    float64         // Creeps this close to a hit take splash damage, 0 for none
 	IsImpacting     bool            // Whether projectile is currently playing impact animation
 }
 
 // ProjectileManager handles all active projectiles
 type ProjectileManager struct {
 	projectiles []Projectile
+	events      *EventBus   // Hits and damage are published here
+	sprites     *SpritePool // Where flight and impact animations come from and go back to
+	nearby      []*Creep    // Scratch space for collision and splash queries, reused every tick
 }
 
 // Constants for projectile system
@@ -37,13 +42,15 @@ const (
 )
 
 // NewProjectileManager creates a new projectile manager
-func NewProjectileManager() *ProjectileManager {
+func NewProjectileManager(events *EventBus, sprites *SpritePool) *ProjectileManager {
 	return &ProjectileManager{
 		projectiles: make([]Projectile, 0),
+		events:      events,
+		sprites:     sprites,
 	}
 }
 
-func (pm *ProjectileManager) SpawnProjectile(startX, startY float64, angle float64, projectileType int) {
+func (pm *ProjectileManager) SpawnProjectile(startX, startY float64, angle float64, projectileType int, sourceTowerID int, damage, splashRadius float64) {
 	// Calculate velocity components
 	velocityX := math.Cos(angle) * projectileSpeed
 	velocityY := math.Sin(angle) * projectileSpeed
@@ -52,15 +59,9 @@ func (pm *ProjectileManager) SpawnProjectile(startX, startY float64, angle float
 	var animation *AnimatedSprite
 	switch projectileType {
 	case BallistaTowerID:
-		if len(assets.BallistaWeaponProjectileAnimation) > 0 {
-			animation = NewAnimatedSprite(assets.BallistaWeaponProjectileAnimation, 0.5, true)
-			animation.Play()
-		}
+		animation = pm.sprites.Get(art.BallistaProjectile)
 	case MagicTowerID:
-		if len(assets.MagicTowerProjectileAnimation) > 0 {
-			animation = NewAnimatedSprite(assets.MagicTowerProjectileAnimation, 0.5, true)
-			animation.Play()
-		}
+		animation = pm.sprites.Get(art.MagicProjectile)
 	}
 
 	projectile := Projectile{
@@ -74,13 +75,16 @@ func (pm *ProjectileManager) SpawnProjectile(startX, startY float64, angle float
 		TravelDistance: 0.0,
 		MaxDistance:    maxProjectileRange,
 		ProjectileType: projectileType,
+		SourceTowerID:  sourceTowerID,
+		Damage:         damage,
+		SplashRadius:   splashRadius,
 		IsImpacting:    false,
 	}
 
 	pm.projectiles = append(pm.projectiles, projectile)
 }
 
-func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, level *TilemapJSON) {
+func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams) {
 
 	for i := range pm.projectiles {
 		projectile := &pm.projectiles[i]
@@ -89,19 +93,22 @@ func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, lev
 			continue
 		}
 
+		var sprite *AnimatedSprite
 		var currentFrame *ebiten.Image
 
 		//Use impact animation if impacting, otherwise use projectile animation
 		if projectile.IsImpacting && projectile.ImpactAnimation != nil {
-			currentFrame = projectile.ImpactAnimation.GetCurrentFrame()
+			sprite = projectile.ImpactAnimation
 		} else if projectile.Animation != nil {
-			currentFrame = projectile.Animation.GetCurrentFrame()
+			sprite = projectile.Animation
+		}
+		if sprite != nil {
+			currentFrame = sprite.GetCurrentFrame()
 		}
 
 		if currentFrame != nil {
 			// Convert tile coordinates to world coordinates
-			worldX := projectile.X * float64(level.TileWidth)
-			worldY := projectile.Y * float64(level.TileHeight)
+			worldX, worldY := params.View.TileToWorld(projectile.X, projectile.Y)
 
 			opts := &ebiten.DrawImageOptions{}
 
@@ -109,9 +116,10 @@ func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, lev
 			frameWidth := float64(currentFrame.Bounds().Dx())
 			frameHeight := float64(currentFrame.Bounds().Dy())
 
-			// Center the projectile on its position
-			centerX := frameWidth / 2.0
-			centerY := frameHeight / 2.0
+			// Place the clip's pivot (the centre, for projectiles) on the projectile's position
+			pivotX, pivotY := sprite.Pivot()
+			centerX := frameWidth * pivotX
+			centerY := frameHeight * pivotY
 
 			// Apply rotation (only for non-impact projectiles)
 			if !projectile.IsImpacting {
@@ -128,7 +136,7 @@ func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, lev
 					rotationAngle = projectile.Angle
 				}
 
-				// Translate to center, rotate to face movement direction, then translate back
+				// Translate to pivot, rotate to face movement direction, then translate back
 				opts.GeoM.Translate(-centerX, -centerY)
 				opts.GeoM.Rotate(rotationAngle)
 				opts.GeoM.Translate(centerX, centerY)
@@ -138,8 +146,7 @@ func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, lev
 			opts.GeoM.Translate(worldX-centerX, worldY-centerY)
 
 			// Apply scaling and screen offset
-			opts.GeoM.Scale(params.Scale, params.Scale)
-			opts.GeoM.Translate(params.OffsetX, params.OffsetY)
+			params.View.Apply(&opts.GeoM)
 
 			screen.DrawImage(currentFrame, opts)
 		}
@@ -148,127 +155,146 @@ func (pm *ProjectileManager) Draw(screen *ebiten.Image, params RenderParams, lev
 
 func (pm *ProjectileManager) startImpactAnimation(projectile *Projectile) {
 	projectile.IsImpacting = true
+	pm.sprites.Put(projectile.Animation)
 	projectile.Animation = nil // Stop projectile animation
 
 	// Create impact animation based on projectile type
 	switch projectile.ProjectileType {
 	case BallistaTowerID:
-		if len(assets.BallisticWeaponImpactAnimation) > 0 {
-			projectile.ImpactAnimation = NewAnimatedSprite(assets.BallisticWeaponImpactAnimation, 0.5, false)
-			projectile.ImpactAnimation.Play()
-		} else {
-			// No impact animation available, just remove projectile
-			projectile.Active = false
-		}
+		projectile.ImpactAnimation = pm.sprites.Get(art.BallistaImpact)
 	case MagicTowerID:
-		if len(assets.MagicTowerProjectileImpactAnimation) > 0 {
-			projectile.ImpactAnimation = NewAnimatedSprite(assets.MagicTowerProjectileImpactAnimation, 0.5, false)
-			projectile.ImpactAnimation.Play()
-		} else {
-			// No impact animation available, just remove projectile
-			projectile.Active = false
-		}
-	default:
-		// Unknown projectile type, just remove
+		projectile.ImpactAnimation = pm.sprites.Get(art.MagicImpact)
+	}
+	if projectile.ImpactAnimation == nil {
+		// No impact animation available, just remove projectile
 		projectile.Active = false
 	}
 }
-func (pm *ProjectileManager) Update(deltaTime float64, activeCreeps []*Creep) {
-	updatedProjectiles := make([]Projectile, 0, len(pm.projectiles))
 
+// Update moves projectiles, lands their hits and plays their impacts. Finished
+// projectiles are dropped by compacting the slice in place and their sprites go
+// back to the pool, so a steady stream of shots doesn't allocate.
+func (pm *ProjectileManager) Update(deltaTime float64, creeps *CreepGrid) {
+	kept := 0
 	for i := range pm.projectiles {
 		projectile := &pm.projectiles[i]
-
-		if !projectile.Active {
-			continue
+		if projectile.Active {
+			pm.updateProjectile(projectile, deltaTime, creeps)
 		}
 
-		// Update projectile animation
-		if projectile.Animation != nil {
-			projectile.Animation.Update(deltaTime)
+		// Keep active projectiles
+		if projectile.Active {
+			pm.projectiles[kept] = *projectile
+			kept++
+		} else {
+			pm.sprites.Put(projectile.Animation)
+			pm.sprites.Put(projectile.ImpactAnimation)
 		}
+	}
+	clear(pm.projectiles[kept:]) // Let go of the sprites the dropped copies point at
+	pm.projectiles = pm.projectiles[:kept]
+}
 
-		// Update impact animation if active
-		if projectile.ImpactAnimation != nil {
-			projectile.ImpactAnimation.Update(deltaTime)
-			if !projectile.ImpactAnimation.IsPlaying() {
-				// Impact animation finished, remove projectile
-				projectile.Active = false
-				continue
-			}
+// Clear removes every projectile, returning their sprites to the pool
+func (pm *ProjectileManager) Clear() {
+	for _, projectile := range pm.projectiles {
+		pm.sprites.Put(projectile.Animation)
+		pm.sprites.Put(projectile.ImpactAnimation)
+	}
+	clear(pm.projectiles)
+	pm.projectiles = pm.projectiles[:0]
+}
+
+// updateProjectile advances one active projectile
+func (pm *ProjectileManager) updateProjectile(projectile *Projectile, deltaTime float64, creeps *CreepGrid) {
+	// Update projectile animation
+	if projectile.Animation != nil {
+		projectile.Animation.Update(deltaTime)
+	}
+
+	// Update impact animation if active
+	if projectile.ImpactAnimation != nil {
+		projectile.ImpactAnimation.Update(deltaTime)
+		if !projectile.ImpactAnimation.IsPlaying() {
+			// Impact animation finished, remove projectile
+			projectile.Active = false
+			return
 		}
+	}
 
-		// Only move projectile if not impacting
-		if !projectile.IsImpacting {
-			// Check for collision with creeps before moving
-			if pm.checkCollisionWithCreeps(projectile, activeCreeps) {
-				// Start impact animation on collision
-				pm.startImpactAnimation(projectile)
-				// Keep projectile for impact animation
-				if projectile.Active {
-					updatedProjectiles = append(updatedProjectiles, *projectile)
-				}
-				continue
-			}
+	// Only move projectile if not impacting
+	if projectile.IsImpacting {
+		return
+	}
 
-			// Move projectile
-			moveDistance := projectileSpeed * deltaTime
-			projectile.X += projectile.VelocityX * deltaTime
-			projectile.Y += projectile.VelocityY * deltaTime
-			projectile.TravelDistance += moveDistance
+	// Check for collision with creeps before moving
+	if pm.checkCollisionWithCreeps(projectile, creeps) {
+		// Start impact animation on collision
+		pm.events.ProjectileHit.Publish(ProjectileHit{X: projectile.X, Y: projectile.Y, ProjectileType: projectile.ProjectileType, HitCreep: true})
+		pm.startImpactAnimation(projectile)
+		return
+	}
 
-			// Update angle to match movement direction
-			projectile.Angle = math.Atan2(projectile.VelocityY, projectile.VelocityX)
+	// Move projectile
+	moveDistance := projectileSpeed * deltaTime
+	projectile.X += projectile.VelocityX * deltaTime
+	projectile.Y += projectile.VelocityY * deltaTime
+	projectile.TravelDistance += moveDistance
 
-			// Check if projectile has traveled maximum distance
-			if projectile.TravelDistance >= projectile.MaxDistance {
-				// Start impact animation
-				pm.startImpactAnimation(projectile)
-			}
-		}
+	// Update angle to match movement direction
+	projectile.Angle = math.Atan2(projectile.VelocityY, projectile.VelocityX)
 
-		// Keep active projectiles
-		if projectile.Active {
-			updatedProjectiles = append(updatedProjectiles, *projectile)
-		}
+	// Check if projectile has traveled maximum distance
+	if projectile.TravelDistance >= projectile.MaxDistance {
+		// Start impact animation
+		pm.events.ProjectileHit.Publish(ProjectileHit{X: projectile.X, Y: projectile.Y, ProjectileType: projectile.ProjectileType})
+		pm.startImpactAnimation(projectile)
 	}
-
-	pm.projectiles = updatedProjectiles
 }
-func (pm *ProjectileManager) checkCollisionWithCreeps(projectile *Projectile, activeCreeps []*Creep) bool {
-	for _, creep := range activeCreeps {
-		if creep == nil || !creep.IsActive() {
-			continue
-		}
 
-		if creep.IsDying {
-			continue // Skip creeps that are already dying
+// checkCollisionWithCreeps damages the closest creep the projectile touches, and
+// any others caught in its splash, and reports whether it hit anything
+func (pm *ProjectileManager) checkCollisionWithCreeps(projectile *Projectile, creeps *CreepGrid) bool {
+	collisionDistance := projectileCollisionRadius + creepCollisionRadius
+	pm.nearby = creeps.AppendInRadius(pm.nearby[:0], projectile.X, projectile.Y, collisionDistance)
+
+	var hit *Creep
+	hitDist := math.MaxFloat64
+	for _, creep := range pm.nearby {
+		if creep.IsDown() {
+			continue // Skip creeps that are already dying or were killed this tick
+		}
+		if dist := math.Hypot(projectile.X-creep.X, projectile.Y-creep.Y); dist < hitDist {
+			hit, hitDist = creep, dist
 		}
+	}
+	if hit == nil {
+		return false // No collision
+	}
+	pm.damageCreep(hit, projectile.Damage, projectile.SourceTowerID)
 
-		// Calculate distance between projectile and creep centers
-		dx := projectile.X - creep.X
-		dy := projectile.Y - creep.Y
-		distance := math.Sqrt(dx*dx + dy*dy)
-
-		// Check if collision occurred
-		collisionDistance := projectileCollisionRadius + creepCollisionRadius
-		if distance <= collisionDistance {
-			// Collision detected! Apply damage to creep
-			var damage float64
-			switch projectile.ProjectileType {
-			case BallistaTowerID:
-				damage = 25.0 // Ballista damage
-			case MagicTowerID:
-				damage = 20.0 // Magic tower damage
-			default:
-				damage = 15.0 // Default damage
+	if projectile.SplashRadius > 0 {
+		pm.nearby = creeps.AppendInRadius(pm.nearby[:0], hit.X, hit.Y, projectile.SplashRadius)
+		for _, creep := range pm.nearby {
+			if creep != hit && !creep.IsDown() {
+				pm.damageCreep(creep, projectile.Damage*splashDamageFactor, projectile.SourceTowerID)
 			}
-
-			// Apply damage to the creep
-			creep.TakeDamage(damage)
-
-			return true // Collision occurred
 		}
 	}
-	return false // No collision
+	return true
+}
+
+// damageCreep applies damage to a creep and reports the health actually removed,
+// and whether this hit finished it off
+func (pm *ProjectileManager) damageCreep(creep *Creep, damage float64, sourceTowerID int) {
+	healthBefore := creep.Health
+	creep.TakeDamage(damage)
+	pm.events.CreepDamaged.Publish(CreepDamaged{
+		CreepID:       creep.ID,
+		X:             creep.X,
+		Y:             creep.Y,
+		Damage:        healthBefore - creep.Health,
+		SourceTowerID: sourceTowerID,
+		Killed:        healthBefore > 0 && creep.Health <= 0,
+	})
 }
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// BenchmarkProjectileManagerUpdate fires a steady stream of ballista bolts and
+// splashing magic orbs from a tower ringed by creeps. The creeps are healed every
+// tick, so the same shots keep landing, killing and missing in a repeating cycle.
+func BenchmarkProjectileManagerUpdate(b *testing.B) {
+	useTestArt(b)
+	const towerX, towerY = 50.5, 50.5
+	const spawnCycle = 32 // Ticks before the firing pattern repeats
+
+	creeps := make([]*Creep, 0, 64)
+	for i := range cap(creeps) {
+		angle := float64(i) * 2 * math.Pi / float64(cap(creeps))
+		dist := 1.5 + float64(i%4)
+		creeps = append(creeps, &Creep{
+			ID:        i + 1,
+			X:         towerX + math.Cos(angle)*dist,
+			Y:         towerY + math.Sin(angle)*dist,
+			Health:    20,
+			MaxHealth: 20,
+			Active:    true,
+		})
+	}
+	grid := NewCreepGrid()
+	grid.Rebuild(creeps, 100, 100)
+
+	pm := NewProjectileManager(NewEventBus(), NewSpritePool())
+	tick := 0
+	benchmarkSteadyState(b, 20*spawnCycle, func() {
+		step := tick % spawnCycle
+		angle := float64(step) * 2 * math.Pi / spawnCycle
+		pm.SpawnProjectile(towerX, towerY, angle, BallistaTowerID, 1, 25, 0)
+		if step%4 == 0 {
+			pm.SpawnProjectile(towerX, towerY, angle+math.Pi, MagicTowerID, 2, 10, 1)
+		}
+		pm.Update(benchTick, grid)
+		for _, creep := range creeps {
+			creep.Health = creep.MaxHealth
+		}
+		tick++
+	})
+}
@@ -1,53 +1,88 @@
 package main
 
 import (
-	"github.com/hajimehoshi/ebiten/v2"
+	"towerDefense/tiled"
 )
 
-// RenderParams holds all the parameters needed for rendering
+const trayWidth = 120 // Width of the tower tray in screen pixels at a UI scale of 1
+
+// RenderParams holds all the parameters needed for rendering.
+// Scale and OffsetX/Y map world pixels to the screen and follow the camera;
+// UIScale and UIOffsetX/Y are the camera-free fit of the map, which the HUD
+// and tray are laid out against so they stay put while the map pans and zooms.
 type RenderParams struct {
 	Scale        float64
 	OffsetX      float64
 	OffsetY      float64
+	UIScale      float64
+	UIOffsetX    float64
+	UIOffsetY    float64
 	TrayX        float64
 	TrayWidth    int
 	ScreenWidth  int
 	ScreenHeight int
+	View         ViewTransform // Tile/world/screen conversions for the world view
+}
+
+// MapViewport describes the part of the screen the map is shown in
+type MapViewport struct {
+	Width, Height       float64 // Screen pixels available to the map (the screen minus the tray)
+	MapWidth, MapHeight float64 // Map size in world pixels
+	FitScale            float64 // Scale at which the whole map fits the viewport
+	OffsetX, OffsetY    float64 // Screen position of the map at FitScale, centred in the viewport
+	TrayX               float64 // Left edge of the tray, right next to the fitted map
+	TrayWidth           int
+	UIScale             float64 // Size of the HUD and tray relative to the fitted map
+	ScreenScale         float64 // Screen pixels per virtual pixel
+	ScreenWidth         int
+	ScreenHeight        int
 }
 
 // Renderer handles map and general rendering logic
-type Renderer struct{}
+type Renderer struct {
+	uiScale     float64 // The player's UI scale setting
+	screenScale float64 // Screen pixels per virtual pixel
+}
 
 // NewRenderer creates a new renderer
 func NewRenderer() *Renderer {
-	return &Renderer{}
+	return &Renderer{uiScale: 1, screenScale: 1}
 }
 
-func (r *Renderer) CalculateRenderParams(screen *ebiten.Image, level *TilemapJSON) RenderParams {
-	const tileSize = 64
-	const trayWidth = 80
+// SetUIScale sets the player's UI scale; the tray widens with it, leaving less room for the map
+func (r *Renderer) SetUIScale(uiScale float64) {
+	r.uiScale = uiScale
+}
 
-	// Get screen dimensions
-	screenWidth, screenHeight := screen.Bounds().Dx(), screen.Bounds().Dy()
+// SetScreenScale sets how many screen pixels there are per virtual pixel. The tray
+// is sized in virtual pixels, and maps are never drawn larger than one world pixel
+// per virtual pixel.
+func (r *Renderer) SetScreenScale(screenScale float64) {
+	r.screenScale = screenScale
+}
 
+// Viewport fits the map next to the tray on a screen of the given layout size
+func (r *Renderer) Viewport(screenWidth, screenHeight int, level *tiled.TilemapJSON) MapViewport {
 	// Calculate available space for the map (minus the tray)
-	mapAreaWidth := screenWidth - trayWidth
+	scaledTrayWidth := int(trayWidth * r.uiScale * r.screenScale)
+	mapAreaWidth := screenWidth - scaledTrayWidth
 
 	// Calculate map dimensions
-	mapWidth := float64(level.Layers[0].Width * tileSize)
-	mapHeight := float64(level.Layers[0].Height * tileSize)
+	cols, rows := level.MapSize()
+	mapWidth := float64(cols * level.TileWidth)
+	mapHeight := float64(rows * level.TileHeight)
 
 	// Ensure map has minimum dimensions to prevent division by zero
 	if mapWidth <= 0 {
-		mapWidth = float64(tileSize)
+		mapWidth = float64(max(level.TileWidth, 1))
 	}
 	if mapHeight <= 0 {
-		mapHeight = float64(tileSize)
+		mapHeight = float64(max(level.TileHeight, 1))
 	}
 
-	// Calculate scale - only scale if map is larger than available area
-	var scale float64 = 1.0
-	if mapWidth > float64(mapAreaWidth) || mapHeight > float64(screenHeight) {
+	// Calculate scale - only shrink if map is larger than available area
+	scale := r.screenScale
+	if mapWidth*scale > float64(mapAreaWidth) || mapHeight*scale > float64(screenHeight) {
 		scaleX := float64(mapAreaWidth) / mapWidth
 		scaleY := float64(screenHeight) / mapHeight
 		scale = scaleX
@@ -68,16 +103,45 @@ func (r *Renderer) CalculateRenderParams(screen *ebiten.Image, level *TilemapJSO
 	offsetX := (float64(mapAreaWidth) - scaledMapWidth) / 2
 	offsetY := (float64(screenHeight) - scaledMapHeight) / 2
 
-	// Calculate tray position (right next to the actual map)
-	trayX := offsetX + scaledMapWidth
-
-	return RenderParams{
-		Scale:        scale,
+	return MapViewport{
+		Width:        float64(mapAreaWidth),
+		Height:       float64(screenHeight),
+		MapWidth:     mapWidth,
+		MapHeight:    mapHeight,
+		FitScale:     scale,
 		OffsetX:      offsetX,
 		OffsetY:      offsetY,
-		TrayX:        trayX,
-		TrayWidth:    trayWidth,
+		TrayX:        offsetX + scaledMapWidth, // Tray sits right next to the actual map
+		TrayWidth:    scaledTrayWidth,
+		UIScale:      scale * r.uiScale,
+		ScreenScale:  r.screenScale,
+		ScreenWidth:  screenWidth,
+		ScreenHeight: screenHeight,
+	}
+}
+
+// CalculateRenderParams works out the world and UI transforms for this frame.
+// With a nil camera the map is simply fitted to the viewport.
+func (r *Renderer) CalculateRenderParams(screenWidth, screenHeight int, level *tiled.TilemapJSON, camera *Camera) RenderParams {
+	vp := r.Viewport(screenWidth, screenHeight, level)
+
+	params := RenderParams{
+		Scale:        vp.FitScale,
+		OffsetX:      vp.OffsetX,
+		OffsetY:      vp.OffsetY,
+		UIScale:      vp.UIScale,
+		UIOffsetX:    vp.OffsetX,
+		UIOffsetY:    vp.OffsetY,
+		TrayX:        vp.TrayX,
+		TrayWidth:    vp.TrayWidth,
 		ScreenWidth:  screenWidth,
 		ScreenHeight: screenHeight,
 	}
+
+	if camera != nil {
+		params.Scale, params.OffsetX, params.OffsetY = camera.Transform(vp)
+	}
+	params.View = NewViewTransform(params, level)
+
+	return params
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// appDirName is the folder under the user's config directory that holds the save and settings
+const appDirName = "towerDefense"
+
+// saveFileName is the single save slot inside appDirName
+const saveFileName = "savegame.json"
+
+// SaveGame is the part of a game that is saved: the level, the player's health and
+// gold and the towers on the map. Creeps are not saved; a loaded game starts a fresh wave.
+type SaveGame struct {
+	Level  string       `json:"level"`
+	Health int          `json:"health"`
+	Gold   int          `json:"gold"`
+	Towers []SavedTower `json:"towers"`
+}
+
+// SavedTower is a placed tower in a save
+type SavedTower struct {
+	X             int           `json:"x"`
+	Y             int           `json:"y"`
+	TowerID       int           `json:"towerId"`
+	TargetingMode TargetingMode `json:"targetingMode"`
+	Kills         int           `json:"kills"`
+	DamageDealt   float64       `json:"damageDealt"`
+}
+
+// appDataDir returns the directory the game keeps its files in, creating it if needed
+func appDataDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(configDir, appDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// WriteSaveGame writes a save to the save slot, replacing what was there
+func WriteSaveGame(save SaveGame) error {
+	dir, err := appDataDir()
+	if err != nil {
+		return fmt.Errorf("finding save directory: %w", err)
+	}
+	data, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash can't leave a half-written save
+	path := filepath.Join(dir, saveFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("writing save: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadSaveGame reads the save slot
+func ReadSaveGame() (SaveGame, error) {
+	var save SaveGame
+	dir, err := appDataDir()
+	if err != nil {
+		return save, fmt.Errorf("finding save directory: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, saveFileName))
+	if err != nil {
+		return save, err
+	}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return save, fmt.Errorf("reading save: %w", err)
+	}
+	return save, nil
+}
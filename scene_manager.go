@@ -1,10 +1,12 @@
 package main
 
 import (
+	"slices"
+
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// SceneType represents different game scenes
+// SceneType names the scenes that can be switched to with TransitionTo
 type SceneType int
 
 const (
@@ -16,63 +18,298 @@ const (
 type Scene interface {
 	Update() error
 	Draw(screen *ebiten.Image)
-	Layout(outerWidth, outerHeight int) (int, int)
+	// Layout tells the scene the size of the screen it will draw to. The scene
+	// manager picks the size, so every scene gets the same one.
+	Layout(screen Screen)
+}
+
+// Scenes can also implement any of these to hear about their place on the stack.
+// OnEnter runs when a scene is pushed or switched to, and OnExit when it is popped
+// or switched away from; a scene that is covered and uncovered by an overlay gets
+// neither.
+type (
+	sceneEnterer interface{ OnEnter() }
+	sceneExiter  interface{ OnExit() }
+)
+
+// overlayScene is implemented by scenes drawn over the scene beneath them, like the
+// pause menu. Any other scene covers everything below it.
+type overlayScene interface{ Overlay() bool }
+
+// TransitionKind is how one scene gives way to the next
+type TransitionKind int
+
+const (
+	TransitionNone      TransitionKind = iota // Swap straight away
+	TransitionFade                            // Fade out to black, then in to the new scene
+	TransitionCrossfade                       // Blend the new scene in over the old one
+	TransitionSlide                           // The new scene pushes the old one off to the left
+	TransitionSlideBack                       // The old scene slides off to the right, uncovering the new one
+)
+
+// Transition is an animated change from one scene stack to another
+type Transition struct {
+	Kind     TransitionKind
+	Duration float64 // Seconds
+}
+
+// Transitions the game uses between its scenes
+var (
+	fadeTransition     = Transition{Kind: TransitionFade, Duration: 0.4}
+	overlayTransition  = Transition{Kind: TransitionCrossfade, Duration: 0.15}
+	slideInTransition  = Transition{Kind: TransitionSlide, Duration: 0.3}
+	slideOutTransition = Transition{Kind: TransitionSlideBack, Duration: 0.3}
+)
+
+// sceneTransition is a transition in progress
+type sceneTransition struct {
+	Transition
+	from    []Scene // The stack before the change, drawn as the outgoing picture
+	elapsed float64
 }
 
 type SceneManager struct {
-	currentScene Scene
-	sceneType    SceneType
+	stack      []Scene          // Bottom scene first; only the top one is updated
+	transition *sceneTransition // Transition being played, nil when there is none
+	fromImage  *ebiten.Image    // Offscreen pictures of the old and new stacks during a transition
+	toImage    *ebiten.Image
+	screen     Screen  // Screen every scene draws to, from the last layout
+	config     *Config // The player's settings, shared by every scene
+	audio      *AudioManager
 
 	// Scene instances
-	titleScene *TitleScene
-	gameScene  *GameScene
-	endScene   *EndScene
+	titleScene    *TitleScene
+	gameScene     *GameScene
+	endScene      *EndScene
+	settingsScene *SettingsScene
 }
 
-// Update updates the current scene
+// Update updates the audio and the scene on top of the stack. Scenes wait while a
+// transition plays, so a click can't land on a scene that is only half shown.
 func (sm *SceneManager) Update() error {
-	return sm.currentScene.Update()
+	deltaTime := 1 / float64(ebiten.TPS())
+	sm.audio.Update(deltaTime)
+
+	if sm.transition != nil {
+		sm.transition.elapsed += deltaTime
+		if sm.transition.elapsed >= sm.transition.Duration {
+			sm.transition = nil
+		}
+		return nil
+	}
+	return sm.Top().Update()
 }
 
-// Draw draws the current scene
+// Draw draws the visible part of the stack, blending the old and new stacks while
+// a transition plays
 func (sm *SceneManager) Draw(screen *ebiten.Image) {
-	sm.currentScene.Draw(screen)
+	if sm.transition == nil {
+		drawStack(screen, sm.stack)
+		return
+	}
+
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	sm.fromImage = offscreen(sm.fromImage, w, h)
+	sm.toImage = offscreen(sm.toImage, w, h)
+	drawStack(sm.fromImage, sm.transition.from)
+	drawStack(sm.toImage, sm.stack)
+	sm.transition.draw(screen, sm.fromImage, sm.toImage)
 }
 
-// Layout returns the screen layout from the current scene
+// Layout is never called by Ebiten since LayoutF is implemented
 func (sm *SceneManager) Layout(outerWidth, outerHeight int) (int, int) {
-	return sm.currentScene.Layout(outerWidth, outerHeight)
+	w, h := sm.LayoutF(float64(outerWidth), float64(outerHeight))
+	return int(w), int(h)
+}
+
+// LayoutF sizes the screen for the window using the player's screen mode and
+// lays out every scene that may be drawn this frame
+func (sm *SceneManager) LayoutF(outerWidth, outerHeight float64) (float64, float64) {
+	sm.screen = screenFor(sm.config.ScreenMode, sm.config.HighDPI, outerWidth, outerHeight)
+	for _, scene := range sm.stack {
+		scene.Layout(sm.screen)
+	}
+	if sm.transition != nil {
+		for _, scene := range sm.transition.from {
+			scene.Layout(sm.screen)
+		}
+	}
+	return float64(sm.screen.Width), float64(sm.screen.Height)
 }
 
-func (sm *SceneManager) GetCurrentSceneType() SceneType {
-	return sm.sceneType
+// Top returns the scene that is being updated
+func (sm *SceneManager) Top() Scene {
+	return sm.stack[len(sm.stack)-1]
 }
 
+// TransitionTo fades to one of the main scenes, closing anything stacked on the current one
 func (sm *SceneManager) TransitionTo(sceneType SceneType) {
-	sm.sceneType = sceneType
-
 	switch sceneType {
 	case SceneTitleScreen:
-		sm.currentScene = sm.titleScene
+		sm.Switch(sm.titleScene, fadeTransition)
 	case SceneGame:
-		sm.currentScene = sm.gameScene
+		sm.Switch(sm.gameScene, fadeTransition)
 	case SceneEndScreen:
-		sm.currentScene = sm.endScene
+		sm.Switch(sm.endScene, fadeTransition)
+	}
+}
+
+// Switch replaces the whole stack with scene
+func (sm *SceneManager) Switch(scene Scene, transition Transition) {
+	old := sm.stack
+	sm.setStack([]Scene{scene}, transition)
+	for i := len(old) - 1; i >= 0; i-- {
+		exitScene(old[i])
+	}
+	enterScene(scene)
+}
+
+// Push puts scene on top of the stack. Overlays are drawn over the scenes below them.
+func (sm *SceneManager) Push(scene Scene, transition Transition) {
+	sm.setStack(append(slices.Clone(sm.stack), scene), transition)
+	enterScene(scene)
+}
+
+// Pop removes the top scene, uncovering the one below. The bottom scene is never popped.
+func (sm *SceneManager) Pop(transition Transition) {
+	if len(sm.stack) <= 1 {
+		return
 	}
+	top := sm.Top()
+	sm.setStack(slices.Clone(sm.stack[:len(sm.stack)-1]), transition)
+	exitScene(top)
 }
 
-func NewSceneManager() *SceneManager {
+// OpenSettings slides the settings screen in over the current scene; it slides
+// back out to it when closed
+func (sm *SceneManager) OpenSettings() {
+	sm.Push(sm.settingsScene, slideInTransition)
+}
+
+// setStack changes the stack, starting the transition unless the player turned them off
+func (sm *SceneManager) setStack(stack []Scene, transition Transition) {
+	from := sm.stack
+	sm.stack = stack
+	if transition.Kind == TransitionNone || transition.Duration <= 0 || !sm.config.SceneTransitions || from == nil {
+		sm.transition = nil
+		return
+	}
+	sm.transition = &sceneTransition{Transition: transition, from: from}
+}
+
+// draw composes the old and new pictures for how far the transition has got
+func (t *sceneTransition) draw(screen, from, to *ebiten.Image) {
+	p := min(t.elapsed/t.Duration, 1)
+	p = p * p * (3 - 2*p) // Ease in and out
+	w := float64(screen.Bounds().Dx())
+
+	op := &ebiten.DrawImageOptions{}
+	switch t.Kind {
+	case TransitionFade:
+		// The old scene darkens over the first half and the new one brightens over the second
+		picture, brightness := from, 1-2*p
+		if p >= 0.5 {
+			picture, brightness = to, 2*p-1
+		}
+		b := float32(brightness)
+		op.ColorScale.Scale(b, b, b, 1)
+		screen.DrawImage(picture, op)
+	case TransitionCrossfade:
+		screen.DrawImage(from, nil)
+		op.ColorScale.ScaleAlpha(float32(p))
+		screen.DrawImage(to, op)
+	case TransitionSlide:
+		op.GeoM.Translate(-p*w, 0)
+		screen.DrawImage(from, op)
+		op.GeoM.Reset()
+		op.GeoM.Translate((1-p)*w, 0)
+		screen.DrawImage(to, op)
+	case TransitionSlideBack:
+		op.GeoM.Translate(p*w, 0)
+		screen.DrawImage(from, op)
+		op.GeoM.Reset()
+		op.GeoM.Translate((p-1)*w, 0)
+		screen.DrawImage(to, op)
+	default:
+		screen.DrawImage(to, nil)
+	}
+}
+
+// drawStack draws the topmost scene that isn't an overlay and every overlay above it
+func drawStack(screen *ebiten.Image, stack []Scene) {
+	first := 0
+	for i := len(stack) - 1; i >= 0; i-- {
+		if overlay, ok := stack[i].(overlayScene); !ok || !overlay.Overlay() {
+			first = i
+			break
+		}
+	}
+	for _, scene := range stack[first:] {
+		scene.Draw(screen)
+	}
+}
+
+// offscreen returns a cleared image of the given size, reusing img when it already fits
+func offscreen(img *ebiten.Image, w, h int) *ebiten.Image {
+	if img != nil && img.Bounds().Dx() == w && img.Bounds().Dy() == h {
+		img.Clear()
+		return img
+	}
+	if img != nil {
+		img.Deallocate()
+	}
+	return ebiten.NewImage(w, h)
+}
+
+func enterScene(scene Scene) {
+	if s, ok := scene.(sceneEnterer); ok {
+		s.OnEnter()
+	}
+}
+
+func exitScene(scene Scene) {
+	if s, ok := scene.(sceneExiter); ok {
+		s.OnExit()
+	}
+}
+
+// NewSceneManager starts on the loading screen, which loads the art and sounds
+// and then builds the other scenes
+func NewSceneManager(config *Config, audio *AudioManager) *SceneManager {
 	sm := &SceneManager{
-		sceneType: SceneTitleScreen,
+		screen: virtualScreen,
+		config: config,
+		audio:  audio,
+	}
+
+	steps := append(gameAssets.AnimationSteps(), audio.LoadSteps()...)
+	sm.Switch(NewLoadingScene(sm, steps, sm.finishLoading), Transition{})
+
+	return sm
+}
+
+// finishLoading builds the scenes once the assets have loaded and fades to the title screen
+func (sm *SceneManager) finishLoading() error {
+	lib, err := gameAssets.Animations()
+	if err != nil {
+		return err
+	}
+	if art, err = loadArt(lib); err != nil {
+		return err
 	}
 
 	// Initialize scenes
 	sm.titleScene = NewTitleScene(sm)
-	sm.gameScene = NewGameScene(sm)
+	if sm.gameScene, err = NewGameScene(sm); err != nil {
+		return err
+	}
 	sm.endScene = NewEndScene(sm)
+	sm.settingsScene = NewSettingsScene(sm, sm.config)
 
-	// Set initial scene
-	sm.currentScene = sm.titleScene
+	// Subscribed after the game scene has set up its first wave behind the title
+	// screen, so that wave starts silently
+	sm.audio.Subscribe(sm.gameScene.events)
 
-	return sm
+	sm.Switch(sm.titleScene, fadeTransition)
+	return nil
 }
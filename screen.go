@@ -0,0 +1,61 @@
+package main
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// The virtual screen every scene is designed for. The real screen is this size
+// scaled to the window, so positions and sizes are written in virtual pixels and
+// multiplied by Screen.Scale.
+const (
+	screenWidth  = 1920
+	screenHeight = 1280
+)
+
+// ScreenMode is how the virtual screen is fitted to the window
+type ScreenMode string
+
+const (
+	ScreenFit    ScreenMode = "fit"    // Keep the virtual aspect ratio, with black bars on the window's long sides
+	ScreenExpand ScreenMode = "expand" // Fill the whole window; scenes get the extra room on the long side
+)
+
+// screenModeOrder is the order the settings screen cycles through the modes
+var screenModeOrder = []ScreenMode{ScreenFit, ScreenExpand}
+
+// screenModeNames are the names the settings screen shows for each mode
+var screenModeNames = map[ScreenMode]string{
+	ScreenFit:    "Letterbox",
+	ScreenExpand: "Fill window",
+}
+
+// Screen is the image the scenes draw to this frame
+type Screen struct {
+	Width, Height int     // Size in pixels
+	Scale         float64 // Pixels per virtual pixel
+}
+
+// virtualScreen is the screen at exactly the virtual size, used before the first layout
+var virtualScreen = Screen{Width: screenWidth, Height: screenHeight, Scale: 1}
+
+// screenFor applies the virtual resolution policy to a window of the given size
+// in device-independent pixels. With highDPI the screen has one pixel per
+// physical pixel of the monitor, so text and sprites stay sharp on high-density
+// displays; without it Ebiten scales a device-independent screen up instead.
+func screenFor(mode ScreenMode, highDPI bool, outerWidth, outerHeight float64) Screen {
+	deviceScale := 1.0
+	if monitor := ebiten.Monitor(); highDPI && monitor != nil {
+		deviceScale = monitor.DeviceScaleFactor()
+	}
+	w := max(outerWidth*deviceScale, 1)
+	h := max(outerHeight*deviceScale, 1)
+	scale := min(w/screenWidth, h/screenHeight)
+
+	if mode == ScreenExpand {
+		return Screen{Width: int(w), Height: int(h), Scale: scale}
+	}
+	// Ebiten letterboxes or pillarboxes a screen whose aspect ratio differs from the window's
+	return Screen{
+		Width:  max(int(screenWidth*scale), 1),
+		Height: max(int(screenHeight*scale), 1),
+		Scale:  scale,
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Choices the settings screen cycles through
+var (
+	resolutionOptions = [][2]int{{960, 640}, {1440, 960}, {1920, 1280}, {2400, 1600}}
+	uiScaleOptions    = []float64{0.75, 1, 1.25, 1.5}
+)
+
+const volumeStep = 0.1 // Each click on a volume raises it by this, wrapping to 0 after full
+
+// reservedKeys can't be bound to actions because the UI, the tray or the console already uses them
+var reservedKeys = []ebiten.Key{
+	ebiten.KeyEscape, ebiten.KeyTab, ebiten.KeyEnter, ebiten.KeySpace, consoleToggleKey,
+	ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5,
+	ebiten.Key6, ebiten.Key7, ebiten.Key8, ebiten.Key9,
+}
+
+// Settings layout, in UI pixels
+const (
+	settingsLabelWidth  = 220.0
+	settingsButtonWidth = 220.0
+	settingsRowSpacing  = 10.0
+	settingsColumnGap   = 60.0
+	settingsFontSize    = 20.0
+)
+
+// settingRow is a setting's value button and how to describe its current value
+type settingRow struct {
+	button *Button
+	value  func() string
+}
+
+// SettingsScene lets the player change the Config. Changes apply straight away
+// and are saved when the player goes back.
+type SettingsScene struct {
+	sceneManager *SceneManager
+	config       *Config
+
+	ui        *UI
+	root      *Panel
+	status    *Label
+	rows      []settingRow
+	firstRow  *Button
+	rebinding Action // Action waiting for a key, "" when none
+	keys      []ebiten.Key
+	scale     float64
+}
+
+func NewSettingsScene(sm *SceneManager, config *Config) *SettingsScene {
+	s := &SettingsScene{sceneManager: sm, config: config, scale: 1}
+
+	general := s.column("General")
+	s.addRow(general, "Resolution", func() string {
+		return fmt.Sprintf("%dx%d", config.WindowWidth, config.WindowHeight)
+	}, s.cycleResolution)
+	s.addRow(general, "Fullscreen", func() string { return onOff(config.Fullscreen) }, func() {
+		config.Fullscreen = !config.Fullscreen
+		ebiten.SetFullscreen(config.Fullscreen)
+	})
+	s.addRow(general, "Screen mode", func() string { return screenModeNames[config.ScreenMode] }, func() {
+		config.ScreenMode = nextOption(screenModeOrder, config.ScreenMode)
+	})
+	s.addRow(general, "High DPI", func() string { return onOff(config.HighDPI) }, func() {
+		config.HighDPI = !config.HighDPI
+	})
+	s.addRow(general, "VSync", func() string { return onOff(config.VSync) }, func() {
+		config.VSync = !config.VSync
+		ebiten.SetVsyncEnabled(config.VSync)
+	})
+	s.addRow(general, "UI scale", func() string { return fmt.Sprintf("%.0f%%", config.UIScale*100) }, func() {
+		config.UIScale = nextOption(uiScaleOptions, config.UIScale)
+	})
+	s.addRow(general, "Starting speed", func() string { return fmt.Sprintf("%dx", config.DefaultSpeed) }, func() {
+		config.DefaultSpeed = nextOption(gameSpeeds, config.DefaultSpeed)
+	})
+	s.addRow(general, "Scene transitions", func() string { return onOff(config.SceneTransitions) }, func() {
+		config.SceneTransitions = !config.SceneTransitions
+	})
+	s.addRow(general, "Creep health bars", func() string { return onOff(config.ShowHealthBars) }, func() {
+		config.ShowHealthBars = !config.ShowHealthBars
+	})
+	s.addRow(general, "Master volume", func() string { return percent(config.MasterVolume) }, func() {
+		config.MasterVolume = nextVolume(config.MasterVolume)
+	})
+	s.addRow(general, "Music volume", func() string { return percent(config.MusicVolume) }, func() {
+		config.MusicVolume = nextVolume(config.MusicVolume)
+	})
+	s.addRow(general, "Effects volume", func() string { return percent(config.EffectsVolume) }, func() {
+		config.EffectsVolume = nextVolume(config.EffectsVolume)
+	})
+
+	keys := s.column("Keys")
+	for _, action := range actionOrder {
+		s.addRow(keys, actionNames[action], func() string {
+			if s.rebinding == action {
+				return "Press a key..."
+			}
+			return config.Keys[action].String()
+		}, func() {
+			s.rebinding = action
+			s.status.Text = "Press a key to bind, or Escape to cancel"
+		})
+	}
+
+	columns := NewPanel(LayoutHorizontal)
+	columns.Spacing = settingsColumnGap
+	columns.Add(general, keys)
+
+	title := NewLabel("Settings", menuTitleSize)
+	title.Font = regularFontSource
+	title.Color = color.RGBA{220, 220, 255, 255}
+	s.status = NewLabel("", settingsFontSize)
+	s.status.Font = regularFontSource
+	s.status.Color = color.RGBA{180, 180, 200, 255}
+
+	buttons := NewPanel(LayoutHorizontal)
+	buttons.Spacing = menuSpacing
+	defaults := NewButton("Reset to defaults", s.resetToDefaults)
+	back := NewButton("Back", s.back)
+	for _, b := range []*Button{defaults, back} {
+		b.Width = settingsButtonWidth
+		b.FontSize = settingsFontSize
+	}
+	buttons.Add(defaults, back)
+
+	page := NewPanel(LayoutVertical)
+	page.Anchor = AnchorCenter
+	page.Align = AlignCenter
+	page.Spacing = menuSpacing
+	page.Add(title, columns, s.status, buttons)
+
+	s.root = NewPanel(LayoutStack)
+	s.root.Add(page)
+	s.ui = NewUI(s.root)
+	s.ui.ArrowKeys = true
+	s.refresh()
+	return s
+}
+
+// column creates a titled column of settings
+func (s *SettingsScene) column(title string) *Panel {
+	heading := NewLabel(title, settingsFontSize)
+	heading.Color = uiAccentColor
+	column := NewPanel(LayoutVertical)
+	column.Spacing = settingsRowSpacing
+	column.Add(heading)
+	return column
+}
+
+// addRow adds a setting with a button that shows its value and changes it when clicked
+func (s *SettingsScene) addRow(column *Panel, name string, value func() string, onClick func()) {
+	label := NewLabel(name, settingsFontSize)
+	label.Width = settingsLabelWidth
+	button := NewButton("", func() {
+		onClick()
+		s.refresh()
+	})
+	button.Width = settingsButtonWidth
+	button.FontSize = settingsFontSize
+
+	row := NewPanel(LayoutHorizontal)
+	row.Align = AlignCenter
+	row.Add(label, button)
+	column.Add(row)
+
+	s.rows = append(s.rows, settingRow{button: button, value: value})
+	if s.firstRow == nil {
+		s.firstRow = button
+	}
+}
+
+// refresh shows every setting's current value
+func (s *SettingsScene) refresh() {
+	for _, row := range s.rows {
+		row.button.Text = row.value()
+	}
+}
+
+// OnEnter starts the settings screen afresh each time it is opened
+func (s *SettingsScene) OnEnter() {
+	s.rebinding = ""
+	s.status.Text = ""
+	s.refresh()
+	s.ui.Focus(s.firstRow)
+}
+
+// cycleResolution moves to the next window size
+func (s *SettingsScene) cycleResolution() {
+	current := slices.Index(resolutionOptions, [2]int{s.config.WindowWidth, s.config.WindowHeight})
+	next := resolutionOptions[(current+1)%len(resolutionOptions)]
+	s.config.WindowWidth, s.config.WindowHeight = next[0], next[1]
+	ebiten.SetWindowSize(next[0], next[1])
+}
+
+// resetToDefaults puts every setting back to its default and applies it
+func (s *SettingsScene) resetToDefaults() {
+	defaults := DefaultConfig()
+	keys := s.config.Keys
+	*s.config = *defaults
+	// Keep the same map so everything holding the bindings sees the change
+	clear(keys)
+	for action, key := range defaults.Keys {
+		keys[action] = key
+	}
+	s.config.Keys = keys
+	s.config.ApplyDisplay()
+	s.rebinding = ""
+	s.status.Text = "Settings reset to defaults"
+	s.refresh()
+}
+
+// back saves the settings and returns to the scene underneath
+func (s *SettingsScene) back() {
+	if err := s.config.Save(); err != nil {
+		s.status.Text = "Could not save settings: " + err.Error()
+		return
+	}
+	s.sceneManager.Pop(slideOutTransition)
+}
+
+// captureKey binds the next key pressed to the action being rebound
+func (s *SettingsScene) captureKey() {
+	s.keys = inpututil.AppendJustPressedKeys(s.keys[:0])
+	if len(s.keys) == 0 {
+		return
+	}
+	key := s.keys[0]
+	switch {
+	case key == ebiten.KeyEscape:
+		s.status.Text = ""
+	case slices.Contains(reservedKeys, key):
+		s.status.Text = fmt.Sprintf("%s is reserved; press another key", key)
+		return
+	default:
+		s.config.Keys.Bind(s.rebinding, key)
+		s.status.Text = ""
+	}
+	s.rebinding = ""
+	s.refresh()
+}
+
+func (s *SettingsScene) Update() error {
+	ebiten.SetCursorMode(ebiten.CursorModeVisible)
+	if s.rebinding != "" {
+		s.captureKey()
+		return nil
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		s.back()
+		return nil
+	}
+	s.ui.Update(s.scale)
+	return nil
+}
+
+func (s *SettingsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{10, 15, 25, 255})
+	s.ui.Draw(screen)
+}
+
+func (s *SettingsScene) Layout(screen Screen) {
+	s.scale = screen.Scale * s.config.UIScale
+	s.root.Arrange(UIRect{W: float64(screen.Width), H: float64(screen.Height)}, s.scale)
+}
+
+// nextOption returns the option after current, wrapping round; a value that
+// isn't one of the options moves to the first
+func nextOption[T comparable](options []T, current T) T {
+	return options[(slices.Index(options, current)+1)%len(options)]
+}
+
+// nextVolume raises a volume by one step, wrapping to 0 after full
+func nextVolume(volume float64) float64 {
+	if volume >= 1-volumeStep/2 {
+		return 0
+	}
+	return min(float64(int(volume/volumeStep+0.5)+1)*volumeStep, 1)
+}
+
+func onOff(on bool) string {
+	if on {
+		return "On"
+	}
+	return "Off"
+}
+
+func percent(fraction float64) string {
+	return fmt.Sprintf("%.0f%%", fraction*100)
+}
@@ -0,0 +1,59 @@
+package tiled
+
+// Map property and object names used by maze levels
+const (
+	mapModeProperty = "mode"
+	mapModeMaze     = "maze"
+	StartObjectName = "start"
+	ExitObjectName  = "exit"
+)
+
+// IsMazeMode reports whether the level has the Tiled map property mode = "maze".
+// Maze levels only mark a start and an exit; creeps find their own way between
+// them and towers block the tiles they stand on.
+func (t *TilemapJSON) IsMazeMode() bool {
+	for _, property := range t.Properties {
+		if property.Name == mapModeProperty {
+			mode, _ := property.Value.(string)
+			return mode == mapModeMaze
+		}
+	}
+	return false
+}
+
+// GetStartAndExit returns the tiles of the "start" and "exit" objects on the waypoints layer.
+// If either is missing the first and last numbered waypoints are used instead.
+func (t *TilemapJSON) GetStartAndExit() (PathNode, PathNode, bool) {
+	var start, exit PathNode
+	hasStart, hasExit := false, false
+
+	for _, layer := range t.Layers {
+		if layer.Type == "objectgroup" && layer.Name == WaypointsLayerName {
+			for _, obj := range layer.Objects {
+				node := t.objectTile(obj)
+				switch obj.Name {
+				case StartObjectName:
+					start, hasStart = node, true
+				case ExitObjectName:
+					exit, hasExit = node, true
+				}
+			}
+			break
+		}
+	}
+
+	if !hasStart || !hasExit {
+		waypoints := t.GetWaypoints()
+		if len(waypoints) < 2 {
+			return PathNode{}, PathNode{}, false
+		}
+		if !hasStart {
+			start = waypoints[0]
+		}
+		if !hasExit {
+			exit = waypoints[len(waypoints)-1]
+		}
+	}
+
+	return start, exit, true
+}
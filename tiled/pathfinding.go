@@ -0,0 +1,115 @@
+package tiled
+
+import (
+	"container/heap"
+	"math"
+)
+
+// SlowTerrainSpeedFactor scales creep speed while on a tile flagged "slow"
+const SlowTerrainSpeedFactor = 0.5
+
+// pathItem is one entry in the A* open set
+type pathItem struct {
+	index int     // Cell index (row*width + col)
+	f     float64 // Cost so far plus heuristic
+}
+
+// pathQueue is a min-heap of open cells ordered by f score
+type pathQueue []pathItem
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].f < q[j].f }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)        { *q = append(*q, x.(pathItem)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// FindPath runs A* over the tile grid and returns the route from start to goal,
+// both included, or nil if the goal cannot be reached. Movement is 4-directional,
+// slow tiles cost more to cross, and blocked decides which tiles are impassable.
+// The start tile itself is never tested against blocked so a creep standing on a
+// freshly blocked tile can still walk off it.
+func FindPath(level *TilemapJSON, start, goal PathNode, blocked func(col, row int) bool) []PathNode {
+	if level == nil {
+		return nil
+	}
+	width, height := level.MapSize()
+
+	if !level.InBounds(start.X, start.Y) || !level.InBounds(goal.X, goal.Y) || blocked(goal.X, goal.Y) {
+		return nil
+	}
+
+	heuristic := func(col, row int) float64 {
+		return math.Abs(float64(col-goal.X)) + math.Abs(float64(row-goal.Y))
+	}
+
+	cellCount := width * height
+	gScore := make([]float64, cellCount)
+	cameFrom := make([]int, cellCount)
+	closed := make([]bool, cellCount)
+	for i := range gScore {
+		gScore[i] = math.Inf(1)
+		cameFrom[i] = -1
+	}
+
+	startIndex := start.Y*width + start.X
+	goalIndex := goal.Y*width + goal.X
+	gScore[startIndex] = 0
+
+	open := &pathQueue{{index: startIndex, f: heuristic(start.X, start.Y)}}
+	neighbours := [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(pathItem)
+		if closed[current.index] {
+			continue // Stale entry, we already found a cheaper way here
+		}
+		if current.index == goalIndex {
+			break
+		}
+		closed[current.index] = true
+
+		col, row := current.index%width, current.index/width
+		for _, n := range neighbours {
+			nextCol, nextRow := col+n[0], row+n[1]
+			if !level.InBounds(nextCol, nextRow) || blocked(nextCol, nextRow) {
+				continue
+			}
+			nextIndex := nextRow*width + nextCol
+			if closed[nextIndex] {
+				continue
+			}
+
+			// Slow terrain costs as much as the time it takes to cross it
+			stepCost := 1.0
+			if level.IsSlow(nextCol, nextRow) {
+				stepCost = 1.0 / SlowTerrainSpeedFactor
+			}
+
+			tentative := gScore[current.index] + stepCost
+			if tentative < gScore[nextIndex] {
+				gScore[nextIndex] = tentative
+				cameFrom[nextIndex] = current.index
+				heap.Push(open, pathItem{index: nextIndex, f: tentative + heuristic(nextCol, nextRow)})
+			}
+		}
+	}
+
+	if math.IsInf(gScore[goalIndex], 1) {
+		return nil
+	}
+
+	// Walk back from the goal and reverse into start-to-goal order
+	var path []PathNode
+	for index := goalIndex; index != -1; index = cameFrom[index] {
+		path = append(path, PathNode{X: index % width, Y: index / width})
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
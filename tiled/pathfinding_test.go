@@ -0,0 +1,198 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"testing/fstest"
+)
+
+// testTileset has a plain ground tile (gid 1), a blocked water tile (gid 2) and
+// a slow mud tile (gid 3)
+const testTileset = `{
+	"name": "test", "columns": 3, "tilecount": 3, "tilewidth": 16, "tileheight": 16,
+	"tiles": [
+		{"id": 1, "properties": [{"name": "blocked", "type": "bool", "value": true}]},
+		{"id": 2, "properties": [{"name": "slow", "type": "bool", "value": true}]}
+	]
+}`
+
+// testLevel loads a maze level drawn as rows of text: '.' is ground, '#' water,
+// '~' mud, 'S' the start and 'E' the exit, both on ground
+func testLevel(t *testing.T, rows ...string) *TilemapJSON {
+	t.Helper()
+	width, height := len(rows[0]), len(rows)
+	data := make([]int, 0, width*height)
+	var objects []TilemapObjectJSON
+	for y, row := range rows {
+		for x, cell := range row {
+			gid := 1
+			switch cell {
+			case '#':
+				gid = 2
+			case '~':
+				gid = 3
+			case 'S':
+				objects = append(objects, TilemapObjectJSON{Name: StartObjectName, X: float64(x*16 + 8), Y: float64(y*16 + 8)})
+			case 'E':
+				objects = append(objects, TilemapObjectJSON{Name: ExitObjectName, X: float64(x*16 + 8), Y: float64(y*16 + 8)})
+			}
+			data = append(data, gid)
+		}
+	}
+
+	level := TilemapJSON{
+		Width: width, Height: height, TileWidth: 16, TileHeight: 16,
+		Layers: []TilemapLayerJSON{
+			{Name: "ground", Type: "tilelayer", Width: width, Height: height, Data: data},
+			{Name: WaypointsLayerName, Type: "objectgroup", Objects: objects},
+		},
+		Tilesets:   []TilemapTilesetRefJSON{{FirstGID: 1, Source: "test.tsj"}},
+		Properties: []TilemapPropertyJSON{{Name: mapModeProperty, Type: "string", Value: mapModeMaze}},
+	}
+	contents, err := json.Marshal(level)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := fstest.MapFS{
+		"map/level.tmj": {Data: contents},
+		"map/test.tsj":  {Data: []byte(testTileset)},
+	}
+	loaded, err := Load(files.ReadFile, "map/level.tmj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+// startAndExit returns a test level's start and exit, failing the test if it lacks them
+func startAndExit(t *testing.T, level *TilemapJSON) (PathNode, PathNode) {
+	t.Helper()
+	start, exit, ok := level.GetStartAndExit()
+	if !ok {
+		t.Fatal("level has no start and exit")
+	}
+	return start, exit
+}
+
+// checkPath fails the test unless path runs from start to goal in single
+// 4-directional steps over tiles that aren't blocked
+func checkPath(t *testing.T, level *TilemapJSON, path []PathNode, start, goal PathNode) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("no path found")
+	}
+	if path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path runs %v to %v, want %v to %v", path[0], path[len(path)-1], start, goal)
+	}
+	for i := 1; i < len(path); i++ {
+		dx, dy := path[i].X-path[i-1].X, path[i].Y-path[i-1].Y
+		if dx*dx+dy*dy != 1 {
+			t.Fatalf("step %d goes from %v to %v", i, path[i-1], path[i])
+		}
+		if level.IsBlocked(path[i].X, path[i].Y) {
+			t.Fatalf("path crosses blocked tile %v", path[i])
+		}
+	}
+}
+
+func TestFindPathStraight(t *testing.T) {
+	level := testLevel(t,
+		".....",
+		"S...E",
+		".....",
+	)
+	start, exit := startAndExit(t, level)
+	path := FindPath(level, start, exit, level.IsBlocked)
+	checkPath(t, level, path, start, exit)
+	if len(path) != 5 {
+		t.Errorf("path has %d nodes, want 5: %v", len(path), path)
+	}
+}
+
+func TestFindPathAroundWater(t *testing.T) {
+	level := testLevel(t,
+		"..#..",
+		"S.#.E",
+		"..#..",
+		".....",
+	)
+	start, exit := startAndExit(t, level)
+	path := FindPath(level, start, exit, level.IsBlocked)
+	checkPath(t, level, path, start, exit)
+	if len(path) != 9 {
+		t.Errorf("path has %d nodes, want 9 through the gap: %v", len(path), path)
+	}
+}
+
+func TestFindPathAvoidsMud(t *testing.T) {
+	level := testLevel(t,
+		"S~~~E",
+		".....",
+	)
+	start, exit := startAndExit(t, level)
+	path := FindPath(level, start, exit, level.IsBlocked)
+	checkPath(t, level, path, start, exit)
+	for _, node := range path {
+		if level.IsSlow(node.X, node.Y) {
+			t.Fatalf("path crosses mud at %v when the way round is quicker: %v", node, path)
+		}
+	}
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	level := testLevel(t,
+		"..#..",
+		"S.#.E",
+		"..#..",
+	)
+	start, exit := startAndExit(t, level)
+	if path := FindPath(level, start, exit, level.IsBlocked); path != nil {
+		t.Errorf("found %v across a wall of water", path)
+	}
+}
+
+func TestFindPathLeavesBlockedStart(t *testing.T) {
+	level := testLevel(t,
+		"S...E",
+	)
+	start, exit := startAndExit(t, level)
+	tower := func(col, row int) bool { return col == start.X && row == start.Y }
+	path := FindPath(level, start, exit, tower)
+	checkPath(t, level, path, start, exit)
+}
+
+// TestFindPathTowerPlacement checks placements the way the game does on maze
+// levels: a tower may go anywhere that leaves the exit reachable
+func TestFindPathTowerPlacement(t *testing.T) {
+	level := testLevel(t,
+		"#####",
+		"S...E",
+		"#.###",
+		"#...#",
+	)
+	start, exit := startAndExit(t, level)
+	tests := []struct {
+		col, row  int
+		reachable bool
+	}{
+		{2, 1, false}, // In the corridor
+		{3, 1, false}, // In front of the exit
+		{1, 2, true},  // In the dead end below
+		{2, 3, true},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d,%d", test.col, test.row), func(t *testing.T) {
+			blocked := func(col, row int) bool {
+				return (col == test.col && row == test.row) || level.IsBlocked(col, row)
+			}
+			path := FindPath(level, start, exit, blocked)
+			if got := path != nil; got != test.reachable {
+				t.Fatalf("exit reachable = %v, want %v (path %v)", got, test.reachable, path)
+			}
+			if path != nil {
+				checkPath(t, level, path, start, exit)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Package tiled loads Tiled maps and tilesets and answers gameplay questions
+// about them (buildability, terrain, waypoints, routes). It has no graphics
+// dependency so tools like cmd/levelcheck can share it with the game.
+package tiled
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// Constants for tile flipping
+const (
+	FlippedHorizontally = 0x80000000
+	FlippedVertically   = 0x40000000
+	FlippedDiagonally   = 0x20000000
+	FlipMask            = FlippedHorizontally | FlippedVertically | FlippedDiagonally
+)
+
+// WaypointsLayerName is the object layer holding the creep route
+const WaypointsLayerName = "waypoints"
+
+// ReadFileFunc reads a file by slash-separated path. The game passes the
+// embedded assets reader, tools pass os.ReadFile.
+type ReadFileFunc func(filepath string) ([]byte, error)
+
+// PathNode represents a single step in the path
+// This is one point in our waypointing logic
+type PathNode struct {
+	X, Y int
+}
+
+// data we want for one layer in our list of layers
+type TilemapLayerJSON struct {
+	Data       []int                 `json:"data"`
+	Width      int                   `json:"width"`
+	Height     int                   `json:"height"`
+	Name       string                `json:"name"`
+	Objects    []TilemapObjectJSON   `json:"objects,omitempty"`
+	Type       string                `json:"type,omitempty"`
+	Properties []TilemapPropertyJSON `json:"properties,omitempty"`
+}
+
+// all layers in a tilemap
+type TilemapJSON struct {
+	Layers     []TilemapLayerJSON      `json:"layers"`
+	Width      int                     `json:"width"`
+	Height     int                     `json:"height"`
+	TileWidth  int                     `json:"tilewidth"`
+	TileHeight int                     `json:"tileheight"`
+	Tilesets   []TilemapTilesetRefJSON `json:"tilesets"`
+	Properties []TilemapPropertyJSON   `json:"properties"`
+
+	tilesets []*TilesetJSON // Loaded external tilesets, parallel to Tilesets (nil if loading failed)
+	flags    []TileFlags    // Precomputed gameplay flags, one per cell
+}
+
+// TilemapPropertyJSON defines the structure for properties within a Tiled object.
+type TilemapPropertyJSON struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value any    `json:"value"` // Using 'any' (or interface{}) for flexibility
+}
+
+// Object definition for object layers (like waypoints)
+type TilemapObjectJSON struct {
+	Name       string                `json:"name"`
+	Type       string                `json:"type"`
+	X          float64               `json:"x"`
+	Y          float64               `json:"y"`
+	Properties []TilemapPropertyJSON `json:"properties"`
+}
+
+// Decode parses a map without loading its tilesets
+func Decode(contents []byte) (*TilemapJSON, error) {
+	var tilemapJSON TilemapJSON
+	err := json.Unmarshal(contents, &tilemapJSON)
+	if err != nil {
+		return nil, err
+	}
+	return &tilemapJSON, nil
+}
+
+// Load opens the file, parses it, loads its tilesets and bakes the gameplay flags
+func Load(readFile ReadFileFunc, filepath string) (*TilemapJSON, error) {
+	contents, err := readFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	tilemapJSON, err := Decode(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the tilesets so we can read their custom properties, then bake the gameplay flags
+	err = tilemapJSON.LoadTilesets(readFile, path.Dir(filepath))
+	if err != nil {
+		return nil, err
+	}
+	tilemapJSON.BuildTileFlags()
+
+	return tilemapJSON, nil
+}
+
+// MapSize returns the map size in tiles, taken from the first layer as the game does
+func (t *TilemapJSON) MapSize() (int, int) {
+	if len(t.Layers) == 0 {
+		return 0, 0
+	}
+	return t.Layers[0].Width, t.Layers[0].Height
+}
+
+// InBounds reports whether a tile lies on the map
+func (t *TilemapJSON) InBounds(col, row int) bool {
+	width, height := t.MapSize()
+	return col >= 0 && row >= 0 && col < width && row < height
+}
+
+// GetWaypoints returns a slice of waypoints from the waypoints layer, sorted numerically by name
+func (t *TilemapJSON) GetWaypoints() []PathNode {
+	waypoints := []struct {
+		Index int
+		Node  PathNode
+	}{}
+
+	for _, layer := range t.Layers {
+		if layer.Type == "objectgroup" && layer.Name == WaypointsLayerName {
+			for _, obj := range layer.Objects {
+				// Try to parse the name as an integer, the same way Validate does
+				idx, err := strconv.Atoi(obj.Name)
+				if err != nil {
+					continue // skip if not a number
+				}
+				waypoints = append(waypoints, struct {
+					Index int
+					Node  PathNode
+				}{idx, t.objectTile(obj)})
+			}
+			break
+		}
+	}
+
+	// Sort by Index
+	sort.Slice(waypoints, func(i, j int) bool {
+		return waypoints[i].Index < waypoints[j].Index
+	})
+
+	// Extract just the PathNodes
+	result := make([]PathNode, len(waypoints))
+	for i, wp := range waypoints {
+		result[i] = wp.Node
+	}
+	return result
+}
+
+// objectTile returns the tile an object sits on
+func (t *TilemapJSON) objectTile(obj TilemapObjectJSON) PathNode {
+	return PathNode{X: int(obj.X) / t.TileWidth, Y: int(obj.Y) / t.TileHeight}
+}
@@ -0,0 +1,246 @@
+package tiled
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+)
+
+// Names of the custom properties we read from Tiled
+const (
+	propertyBuildable = "buildable"
+	propertySlow      = "slow"
+	propertyBlocked   = "blocked"
+)
+
+// BuildableLayerName is the optional tile layer that, when present, decides
+// buildability on its own: any non-empty tile is buildable, everything else is not.
+const BuildableLayerName = "buildable"
+
+// TileFlags holds the gameplay flags for a single map cell
+type TileFlags uint8
+
+const (
+	TileBuildable TileFlags = 1 << iota // Towers may be placed here
+	TileSlow                            // Creeps move at reduced speed
+	TileBlocked                         // Creeps cannot walk here
+)
+
+// TilemapTilesetRefJSON is a tileset reference inside a map file
+type TilemapTilesetRefJSON struct {
+	FirstGID int    `json:"firstgid"`
+	Source   string `json:"source"`
+}
+
+// TilesetTileJSON holds the per-tile data of a tileset (only properties for now)
+type TilesetTileJSON struct {
+	ID         int                   `json:"id"`
+	Properties []TilemapPropertyJSON `json:"properties"`
+}
+
+// TilesetJSON is an external Tiled tileset (.tsj)
+type TilesetJSON struct {
+	Name       string                `json:"name"`
+	Image      string                `json:"image"`
+	Columns    int                   `json:"columns"`
+	TileCount  int                   `json:"tilecount"`
+	TileWidth  int                   `json:"tilewidth"`
+	TileHeight int                   `json:"tileheight"`
+	Margin     int                   `json:"margin"`
+	Spacing    int                   `json:"spacing"`
+	Properties []TilemapPropertyJSON `json:"properties"`
+	Tiles      []TilesetTileJSON     `json:"tiles"`
+
+	// ImagePath is Image resolved against the tileset file's directory, the path
+	// to load the sheet from
+	ImagePath string `json:"-"`
+
+	firstGID int
+}
+
+// loadTileset reads an external tileset
+func loadTileset(readFile ReadFileFunc, filepath string, firstGID int) (*TilesetJSON, error) {
+	contents, err := readFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	var tileset TilesetJSON
+	err = json.Unmarshal(contents, &tileset)
+	if err != nil {
+		return nil, err
+	}
+	tileset.firstGID = firstGID
+	tileset.ImagePath = path.Join(path.Dir(filepath), tileset.Image)
+
+	return &tileset, nil
+}
+
+// LoadTilesets loads every tileset referenced by the map, resolving sources relative to dir.
+// Tilesets that fail to load are left out and all the failures are returned together.
+func (t *TilemapJSON) LoadTilesets(readFile ReadFileFunc, dir string) error {
+	var errs []error
+	t.tilesets = make([]*TilesetJSON, len(t.Tilesets))
+	for i, ref := range t.Tilesets {
+		tileset, err := loadTileset(readFile, path.Join(dir, ref.Source), ref.FirstGID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tileset %q: %w", ref.Source, err))
+			continue
+		}
+		t.tilesets[i] = tileset
+	}
+	return errors.Join(errs...)
+}
+
+// tilesetRefForGID returns the index of the tileset reference a (flag-free) global
+// tile ID falls in, or -1 if it is below every tileset's first ID
+func (t *TilemapJSON) tilesetRefForGID(gid int) int {
+	found := -1
+	for i, ref := range t.Tilesets {
+		if gid >= ref.FirstGID && (found == -1 || ref.FirstGID > t.Tilesets[found].FirstGID) {
+			found = i
+		}
+	}
+	return found
+}
+
+// tilesetForGID returns the loaded tileset a (flag-free) global tile ID belongs to
+func (t *TilemapJSON) tilesetForGID(gid int) *TilesetJSON {
+	index := t.tilesetRefForGID(gid)
+	if index < 0 || index >= len(t.tilesets) {
+		return nil
+	}
+	return t.tilesets[index]
+}
+
+// TilesetForGID returns the loaded tileset a (flag-free) global tile ID belongs
+// to and the tile's local ID within it, or nil if no loaded tileset covers it
+func (t *TilemapJSON) TilesetForGID(gid int) (*TilesetJSON, int) {
+	tileset := t.tilesetForGID(gid)
+	if tileset == nil || gid-tileset.firstGID >= tileset.TileCount {
+		return nil, 0
+	}
+	return tileset, gid - tileset.firstGID
+}
+
+// applyTileProperties applies the tileset-wide and then the per-tile properties for a tile
+func (t *TilemapJSON) applyTileProperties(flags TileFlags, tileID int) TileFlags {
+	gid := tileID &^ FlipMask
+	tileset := t.tilesetForGID(gid)
+	if tileset == nil {
+		return flags
+	}
+
+	flags = applyFlagProperties(flags, tileset.Properties)
+
+	localID := gid - tileset.firstGID
+	for _, tile := range tileset.Tiles {
+		if tile.ID == localID {
+			flags = applyFlagProperties(flags, tile.Properties)
+			break
+		}
+	}
+	return flags
+}
+
+// applyFlagProperties sets or clears flags from any boolean gameplay properties present
+func applyFlagProperties(flags TileFlags, properties []TilemapPropertyJSON) TileFlags {
+	for _, property := range properties {
+		value, ok := property.Value.(bool)
+		if !ok {
+			continue
+		}
+
+		var flag TileFlags
+		switch property.Name {
+		case propertyBuildable:
+			flag = TileBuildable
+		case propertySlow:
+			flag = TileSlow
+		case propertyBlocked:
+			flag = TileBlocked
+		default:
+			continue
+		}
+
+		if value {
+			flags |= flag
+		} else {
+			flags &^= flag
+		}
+	}
+	return flags
+}
+
+// BuildTileFlags precomputes the gameplay flags for every cell of the map.
+// Cells start out buildable; tile layers are then applied bottom to top so a
+// later layer (a bridge over water, say) overrides an earlier one. For each
+// non-empty tile the tileset properties apply first, then the tile's own
+// properties, then the properties of the layer it sits on.
+func (t *TilemapJSON) BuildTileFlags() {
+	width, height := t.MapSize()
+	t.flags = make([]TileFlags, width*height)
+	for i := range t.flags {
+		t.flags[i] = TileBuildable
+	}
+
+	var buildableLayer *TilemapLayerJSON
+	for i := range t.Layers {
+		layer := &t.Layers[i]
+		if layer.Type == "objectgroup" {
+			continue
+		}
+		if layer.Name == BuildableLayerName {
+			buildableLayer = layer
+			continue
+		}
+
+		for index, tileID := range layer.Data {
+			if tileID == 0 || index >= len(t.flags) {
+				continue
+			}
+			flags := t.applyTileProperties(t.flags[index], tileID)
+			t.flags[index] = applyFlagProperties(flags, layer.Properties)
+		}
+	}
+
+	// The dedicated layer, if there is one, has the final word on buildability
+	if buildableLayer != nil {
+		for index := range t.flags {
+			if index < len(buildableLayer.Data) && buildableLayer.Data[index] != 0 {
+				t.flags[index] |= TileBuildable
+			} else {
+				t.flags[index] &^= TileBuildable
+			}
+		}
+	}
+}
+
+// TileFlagsAt returns the precomputed flags for a cell, or TileBlocked when off the map
+func (t *TilemapJSON) TileFlagsAt(col, row int) TileFlags {
+	if !t.InBounds(col, row) {
+		return TileBlocked
+	}
+	width, _ := t.MapSize()
+	index := row*width + col
+	if index >= len(t.flags) {
+		return TileBlocked
+	}
+	return t.flags[index]
+}
+
+// IsBuildable reports whether the map allows a tower on this cell (ignoring towers already placed)
+func (t *TilemapJSON) IsBuildable(col, row int) bool {
+	return t.TileFlagsAt(col, row)&TileBuildable != 0
+}
+
+// IsSlow reports whether creeps are slowed on this cell
+func (t *TilemapJSON) IsSlow(col, row int) bool {
+	return t.TileFlagsAt(col, row)&TileSlow != 0
+}
+
+// IsBlocked reports whether creeps are unable to walk on this cell
+func (t *TilemapJSON) IsBlocked(col, row int) bool {
+	return t.TileFlagsAt(col, row)&TileBlocked != 0
+}
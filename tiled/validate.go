@@ -0,0 +1,172 @@
+package tiled
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Validate checks a map (with its tilesets already loaded) for the mistakes that
+// otherwise only show up as panics or silent misbehaviour in the game. It returns
+// one error per problem found; an empty result means the map is good to ship.
+func (t *TilemapJSON) Validate() []error {
+	var errs []error
+	errs = append(errs, t.validateLayers()...)
+	errs = append(errs, t.validateTiles()...)
+	errs = append(errs, t.validateWaypoints()...)
+	errs = append(errs, t.validateRoute()...)
+	errs = append(errs, t.validateBuildable()...)
+	return errs
+}
+
+// validateLayers checks every tile layer matches the map size
+func (t *TilemapJSON) validateLayers() []error {
+	var errs []error
+	tileLayers := 0
+	for _, layer := range t.Layers {
+		if layer.Type == "objectgroup" {
+			continue
+		}
+		tileLayers++
+		if layer.Width != t.Width || layer.Height != t.Height {
+			errs = append(errs, fmt.Errorf("layer %q is %dx%d but the map is %dx%d",
+				layer.Name, layer.Width, layer.Height, t.Width, t.Height))
+		}
+		if len(layer.Data) != layer.Width*layer.Height {
+			errs = append(errs, fmt.Errorf("layer %q has %d tiles, expected %d",
+				layer.Name, len(layer.Data), layer.Width*layer.Height))
+		}
+	}
+	if tileLayers == 0 {
+		errs = append(errs, fmt.Errorf("map has no tile layers"))
+	}
+	if t.TileWidth <= 0 || t.TileHeight <= 0 {
+		errs = append(errs, fmt.Errorf("map tile size %dx%d is invalid", t.TileWidth, t.TileHeight))
+	}
+	return errs
+}
+
+// validateTiles reports tile IDs that no loaded tileset covers, once per layer and ID
+func (t *TilemapJSON) validateTiles() []error {
+	var errs []error
+	for _, layer := range t.Layers {
+		unknown := map[int]int{} // gid -> first cell index it appeared at
+		for index, tileID := range layer.Data {
+			if tileID == 0 {
+				continue
+			}
+			gid := tileID &^ FlipMask
+			ref := t.tilesetRefForGID(gid)
+			tileset := t.tilesetForGID(gid)
+			if ref >= 0 && tileset == nil {
+				continue // Its tileset is missing, which LoadTilesets already reported
+			}
+			if ref < 0 || gid-tileset.firstGID >= tileset.TileCount {
+				if _, seen := unknown[gid]; !seen {
+					unknown[gid] = index
+				}
+			}
+		}
+
+		gids := make([]int, 0, len(unknown))
+		for gid := range unknown {
+			gids = append(gids, gid)
+		}
+		sort.Ints(gids)
+		for _, gid := range gids {
+			index := unknown[gid]
+			errs = append(errs, fmt.Errorf("layer %q uses unknown tile ID %d (first at tile %d,%d)",
+				layer.Name, gid, index%max(layer.Width, 1), index/max(layer.Width, 1)))
+		}
+	}
+	return errs
+}
+
+// validateWaypoints checks the waypoints layer is present and every waypoint is
+// numbered, unique, on the map and on walkable ground
+func (t *TilemapJSON) validateWaypoints() []error {
+	var layer *TilemapLayerJSON
+	for i := range t.Layers {
+		if t.Layers[i].Type == "objectgroup" && t.Layers[i].Name == WaypointsLayerName {
+			layer = &t.Layers[i]
+			break
+		}
+	}
+	if layer == nil {
+		return []error{fmt.Errorf("no %q object layer", WaypointsLayerName)}
+	}
+
+	var errs []error
+	seen := map[int]bool{}
+	numbered := 0
+	for _, obj := range layer.Objects {
+		isMarker := obj.Name == StartObjectName || obj.Name == ExitObjectName
+		if !isMarker {
+			idx, err := strconv.Atoi(obj.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("waypoint %q is not a number", obj.Name))
+				continue
+			}
+			if seen[idx] {
+				errs = append(errs, fmt.Errorf("waypoint %d is defined more than once", idx))
+			}
+			seen[idx] = true
+			numbered++
+		}
+
+		if t.TileWidth <= 0 || t.TileHeight <= 0 {
+			continue // Can't tell which tile it is on; validateLayers reported the tile size
+		}
+		mapPixelWidth := float64(t.Width * t.TileWidth)
+		mapPixelHeight := float64(t.Height * t.TileHeight)
+		if obj.X < 0 || obj.Y < 0 || obj.X >= mapPixelWidth || obj.Y >= mapPixelHeight {
+			errs = append(errs, fmt.Errorf("waypoint %q at %.0f,%.0f is off the map", obj.Name, obj.X, obj.Y))
+			continue
+		}
+		tile := t.objectTile(obj)
+		if t.IsBlocked(tile.X, tile.Y) {
+			errs = append(errs, fmt.Errorf("waypoint %q is on blocked terrain at tile %d,%d", obj.Name, tile.X, tile.Y))
+		}
+	}
+
+	if !t.IsMazeMode() && numbered < 2 {
+		errs = append(errs, fmt.Errorf("need at least 2 numbered waypoints, found %d", numbered))
+	}
+	return errs
+}
+
+// validateRoute checks creeps can actually walk from the start to the exit
+func (t *TilemapJSON) validateRoute() []error {
+	if t.TileWidth <= 0 || t.TileHeight <= 0 {
+		return nil // The start and exit can't be placed; validateLayers reported the tile size
+	}
+	start, exit, ok := t.GetStartAndExit()
+	if !ok {
+		if t.IsMazeMode() {
+			return []error{fmt.Errorf("maze level needs %q and %q waypoints", StartObjectName, ExitObjectName)}
+		}
+		return nil // Already reported by validateWaypoints
+	}
+	if !t.InBounds(start.X, start.Y) || !t.InBounds(exit.X, exit.Y) {
+		return nil // Already reported by validateWaypoints
+	}
+
+	if FindPath(t, start, exit, t.IsBlocked) == nil {
+		return []error{fmt.Errorf("exit at tile %d,%d can't be reached from the start at tile %d,%d",
+			exit.X, exit.Y, start.X, start.Y)}
+	}
+	return nil
+}
+
+// validateBuildable checks there is somewhere to put a tower
+func (t *TilemapJSON) validateBuildable() []error {
+	width, height := t.MapSize()
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			if t.IsBuildable(col, row) {
+				return nil
+			}
+		}
+	}
+	return []error{fmt.Errorf("map has no buildable tiles")}
+}
@@ -0,0 +1,49 @@
+package tiled
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateZeroTileSize(t *testing.T) {
+	level := testLevel(t,
+		"S...E",
+	)
+	level.TileWidth = 0
+
+	errs := level.Validate() // Must report the tile size rather than divide by it
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "tile size") {
+		t.Fatalf("got %v, want just the tile size error", errs)
+	}
+}
+
+// TestWaypointNames checks the game and the validator agree on which objects
+// are numbered waypoints
+func TestWaypointNames(t *testing.T) {
+	level := &TilemapJSON{
+		TileWidth: 16, TileHeight: 16,
+		Layers: []TilemapLayerJSON{{
+			Name: WaypointsLayerName,
+			Type: "objectgroup",
+			Objects: []TilemapObjectJSON{
+				{Name: "2", X: 40, Y: 8},
+				{Name: "12abc", X: 72, Y: 8},
+				{Name: "1", X: 8, Y: 8},
+			},
+		}},
+	}
+
+	want := []PathNode{{X: 0, Y: 0}, {X: 2, Y: 0}}
+	got := level.GetWaypoints()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("GetWaypoints() = %v, want %v", got, want)
+	}
+
+	found := false
+	for _, err := range level.validateWaypoints() {
+		found = found || strings.Contains(err.Error(), `"12abc" is not a number`)
+	}
+	if !found {
+		t.Errorf("validateWaypoints() didn't report the waypoint named 12abc")
+	}
+}
@@ -1,94 +1,59 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"image/color"
+	"io/fs"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"golang.org/x/image/font/gofont/goregular"
 )
 
 type TitleScene struct {
 	sceneManager *SceneManager
-	titleFont    *text.GoTextFace
-	subtitleFont *text.GoTextFace
+	menu         *Menu
+	quit         bool
 }
 
 func (t *TitleScene) Draw(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{10, 15, 25, 255})
-
-	// Get screen dimensions
-	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
-
-	// Draw title
-	titleText := "Tower Defenders"
-	titleBounds, _ := text.Measure(titleText, t.titleFont, 0)
-	titleX := (w - int(titleBounds)) / 2
-	titleY := h/2 - 50
-
-	op := &text.DrawOptions{}
-	op.GeoM.Translate(float64(titleX), float64(titleY))
-	op.ColorScale.ScaleWithColor(color.RGBA{220, 220, 255, 255})
-	text.Draw(screen, titleText, t.titleFont, op)
-
-	// Draw subtitle
-	subtitleText := "Press any key to Start"
-	subtitleBounds, _ := text.Measure(subtitleText, t.subtitleFont, 0)
-	subtitleX := (w - int(subtitleBounds)) / 2
-	subtitleY := titleY + 80
-
-	op2 := &text.DrawOptions{}
-	op2.GeoM.Translate(float64(subtitleX), float64(subtitleY))
-	op2.ColorScale.ScaleWithColor(color.RGBA{180, 180, 200, 255})
-	text.Draw(screen, subtitleText, t.subtitleFont, op2)
+	t.menu.Draw(screen)
 }
 
 func (t *TitleScene) Update() error {
-	// Check for key presses
-	if ebiten.IsKeyPressed(ebiten.KeySpace) ||
-		ebiten.IsKeyPressed(ebiten.KeyEnter) ||
-		ebiten.IsKeyPressed(ebiten.KeyEscape) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyA) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyS) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyD) ||
-		inpututil.IsKeyJustPressed(ebiten.KeyW) {
-		t.sceneManager.TransitionTo(SceneGame)
-		return nil
-	}
-
-	// Check for mouse clicks
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) ||
-		inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
-		t.sceneManager.TransitionTo(SceneGame)
-		return nil
+	if t.quit {
+		return ebiten.Termination
 	}
-
+	t.menu.Update()
 	return nil
 }
 
-func (t *TitleScene) Layout(outerWidth, outerHeight int) (int, int) {
-	return outerWidth, outerHeight
+// OnEnter starts the title music
+func (t *TitleScene) OnEnter() {
+	t.sceneManager.audio.PlayMusic(MusicTitle)
 }
 
-func NewTitleScene(sm *SceneManager) *TitleScene {
-	// Create fonts
-	titleFontSource, _ := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
-	titleFont := &text.GoTextFace{
-		Source: titleFontSource,
-		Size:   48,
-	}
-
-	subtitleFontSource, _ := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
-	subtitleFont := &text.GoTextFace{
-		Source: subtitleFontSource,
-		Size:   24,
-	}
+func (t *TitleScene) Layout(screen Screen) {
+	t.menu.Layout(screen, t.sceneManager.config.UIScale)
+}
 
-	return &TitleScene{
-		sceneManager: sm,
-		titleFont:    titleFont,
-		subtitleFont: subtitleFont,
-	}
+func NewTitleScene(sm *SceneManager) *TitleScene {
+	t := &TitleScene{sceneManager: sm}
+	t.menu = NewMenu(
+		"Tower Defenders", color.RGBA{220, 220, 255, 255},
+		"Defend the road from the creeps", color.RGBA{180, 180, 200, 255},
+		NewButton("Start", func() { sm.TransitionTo(SceneGame) }),
+		NewButton("Continue", func() {
+			if err := sm.gameScene.Load(); errors.Is(err, fs.ErrNotExist) {
+				t.menu.SetSubtitle("No saved game to continue")
+				return
+			} else if err != nil {
+				t.menu.SetSubtitle("Could not load the saved game")
+				return
+			}
+			sm.TransitionTo(SceneGame)
+		}),
+		NewButton("Settings", sm.OpenSettings),
+		NewButton("Quit", func() { t.quit = true }),
+	)
+	return t
 }
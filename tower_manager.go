@@ -1,8 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"math"
-	"towerDefense/assets"
+	"towerDefense/tiled"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
@@ -18,13 +19,20 @@ const weaponRotationSpeed = 3.0      // Radians per second for weapon rotation
 const weaponRotationSmoothness = 8.0 // Higher values = smoother but slower rotation
 const towerRange = 5.0               // Range in tiles for tower attacks
 const fireDelay = 1.5                // Seconds between shots
-const fireAnimationDuration = 0.5    // Duration of firing animation (50% faster)
 
 type TowerManager struct {
-	towers             []*ebiten.Image
 	placedTowers       []PlacedTower
-	buildingAnimations []*BuildingAnimationState
+	buildingAnimations []BuildingAnimationState
 	projectileManager  *ProjectileManager
+	placementValidator func(col, row int) bool // Extra placement rule supplied by the scene (maze routing)
+	onTowersChanged    func()                  // Called when a tower starts building or is sold
+	nextTowerID        int                     // Unique ID handed to the next placed tower
+	inspectedTowerID   int                     // Placed tower shown in the info panel, 0 for none
+	events             *EventBus               // Towers built and fired are published here
+	unsubscribe        func()                  // Stops recording hits from the event bus
+	inRange            []*Creep                // Scratch space for target selection, reused every tick
+	sprites            *SpritePool             // Recycles building, weapon, projectile and impact animations
+	keys               KeyBindings
 }
 
 // BuildingAnimationState holds the state for a tower being built
@@ -40,8 +48,16 @@ type PlacedTower struct {
 	Image           *ebiten.Image
 	X               int // Grid position X
 	Y               int // Grid position Y
-	TowerID         int // Which tower type (index in towers array)
-	Damage          float64
+	ID              int // Unique ID, used to credit projectile hits back to the tower
+	TowerID         int // Which tower type (key into towerDefinitions)
+	Level           int
+	Damage          float64         // Damage per projectile hit
+	FireDelay       float64         // Seconds between shots
+	Range           float64         // Attack range in tiles
+	SplashRadius    float64         // Splash damage radius in tiles, 0 for none
+	TargetingMode   TargetingMode   // Which creep in range to shoot at
+	Kills           int             // Creeps finished off by this tower's projectiles
+	DamageDealt     float64         // Total health taken off creeps
 	WeaponImage     *ebiten.Image   // Image for the tower's weapon, if any
 	WeaponAngle     float64         // Current angle of the weapon in radians. 0 = East, -PI/2 = North.
 	FireTimer       float64         // Time remaining before weapon can fire again
@@ -50,151 +66,55 @@ type PlacedTower struct {
 	WeaponFired     bool            // Flag to indicate if weapon has been fired and needs to spawn a projectile
 	TargetX         float64         // X position of target when weapon was fired
 	TargetY         float64         // Y position of target when weapon was fired
+	TargetCreepID   int             // Creep the weapon is aiming at, 0 for none
 }
 
-const (
-	waterFirstTileIDLocal = 257
-	flipMask              = 0x80000000 | 0x40000000 | 0x20000000
-	towerCost             = 75 // Cost to place a tower
-)
-
-func NewTowerManager() *TowerManager {
-	return &TowerManager{
-		towers: []*ebiten.Image{
-			assets.NoneIndicator,
-			assets.BallistaTower,
-			assets.MagicTower,
-		},
+func NewTowerManager(events *EventBus) *TowerManager {
+	sprites := NewSpritePool()
+	tm := &TowerManager{
 		placedTowers:       make([]PlacedTower, 0),
-		buildingAnimations: make([]*BuildingAnimationState, 0),
-		projectileManager:  &ProjectileManager{},
-	}
+		buildingAnimations: make([]BuildingAnimationState, 0),
+		projectileManager:  NewProjectileManager(events, sprites),
+		sprites:            sprites,
+		nextTowerID:        1,
+		keys:               defaultKeyBindings(),
+		events:             events,
+	}
+	tm.unsubscribe = events.CreepDamaged.Subscribe(tm.recordHit)
+	return tm
 }
 
-// drawTrayBackground renders the tray background image
-func (tm *TowerManager) drawTrayBackground(screen *ebiten.Image, params RenderParams) {
-	trayOpts := &ebiten.DrawImageOptions{}
-
-	// Scale the tray image to fit the available space
-	trayImageBounds := assets.TrayBackground.Bounds()
-	trayImageWidth := float64(trayImageBounds.Dx())
-	trayImageHeight := float64(trayImageBounds.Dy())
-
-	// Calculate scale to fit the tray area
-	trayScaleX := float64(params.TrayWidth) / trayImageWidth
-	trayScaleY := float64(params.ScreenHeight) / trayImageHeight
-
-	trayOpts.GeoM.Scale(trayScaleX, trayScaleY)
-	trayOpts.GeoM.Translate(params.TrayX, 0)
-	screen.DrawImage(assets.TrayBackground, trayOpts)
+// Close stops the tower manager listening to the event bus, which outlives it
+func (tm *TowerManager) Close() {
+	tm.unsubscribe()
 }
 
-// DrawTowerTray renders the tower selection tray
-func (tm *TowerManager) DrawTowerTray(screen *ebiten.Image, params RenderParams, selectedTower int, uiManager *UIManager) {
-	// Draw the tray background
-	tm.drawTrayBackground(screen, params)
-	tm.drawTowerOptions(screen, params)
+// SetPlacementValidator sets an extra rule a tile must pass before a tower can be placed on it
+func (tm *TowerManager) SetPlacementValidator(cb func(col, row int) bool) {
+	tm.placementValidator = cb
 }
 
-// drawTowerOptions renders individual tower options in the tray
-func (tm *TowerManager) drawTowerOptions(screen *ebiten.Image, params RenderParams) {
-	const baseTowerSpacing = 140.0 // Reduced back to original spacing since no upgrade buttons
-	const baseTowerStartY = 20.0
-	const baseTowerWidth = 64.0
-	const baseTowerHeight = 128.0
-
-	scaledTowerSpacing := baseTowerSpacing * params.Scale
-	scaledTowerStartY := baseTowerStartY * params.Scale
-	scaledTowerWidth := baseTowerWidth * params.Scale
-	scaledTowerHeight := baseTowerHeight * params.Scale
-
-	for i, towerImg := range tm.towers {
-		if towerImg == nil {
-			continue
-		}
-
-		// Calculate tower position
-		towerX := params.TrayX + (float64(params.TrayWidth)-scaledTowerWidth)/2
-		towerY := scaledTowerStartY + float64(i)*scaledTowerSpacing
-
-		// Only draw if the tower fits within the screen
-		if towerY+scaledTowerHeight > float64(params.ScreenHeight) {
-			continue
-		}
-
-		// Draw the tower image
-		towerOpts := &ebiten.DrawImageOptions{}
-		towerOpts.GeoM.Scale(params.Scale, params.Scale)
-		towerOpts.GeoM.Translate(towerX, towerY)
-
-		screen.DrawImage(towerImg, towerOpts)
-	}
+// SetKeyBindings sets the keys the tower manager listens to
+func (tm *TowerManager) SetKeyBindings(keys KeyBindings) {
+	tm.keys = keys
 }
 
-// HandleTowerSelection handles clicks on the tower tray
-func (tm *TowerManager) HandleTowerSelection(level *TilemapJSON, currentGold int, params RenderParams) (bool, int) {
-	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		return false, 0
-	}
-
-	mouseX, mouseY := ebiten.CursorPosition()
-
-	// Check if click is in the tray area
-	if float64(mouseX) < params.TrayX || mouseX > params.ScreenWidth {
-		return false, 0
-	}
-
-	// Calculate which tower was clicked
-	const baseTowerSpacing = 140.0 // Updated to match drawTowerOptions
-	const baseTowerStartY = 20.0
-	const baseTowerHeight = 128.0
-
-	scaledTowerSpacing := baseTowerSpacing * params.Scale
-	scaledTowerStartY := baseTowerStartY * params.Scale
-	scaledTowerHeight := baseTowerHeight * params.Scale
-
-	relativeY := float64(mouseY) - scaledTowerStartY
-	if relativeY < 0 {
-		return false, 0
-	}
-
-	towerIndex := int(relativeY / scaledTowerSpacing)
-	if towerIndex < 0 || towerIndex >= len(tm.towers) {
-		return false, 0
-	}
-
-	// Check if click is within tower area only (not in button area)
-	towerOffset := relativeY - float64(towerIndex)*scaledTowerSpacing
-
-	// Only select tower if click is within tower image area, not button area
-	if towerOffset < 0 || towerOffset > scaledTowerHeight {
-		return false, 0
-	}
-
-	// Don't allow selection of towers (except "none") if player can't afford them
-	if towerIndex > 0 && currentGold < towerCost {
-		return false, 0 // Not enough gold to select tower
-	}
-
-	// Tower selected successfully
-	return true, towerIndex
+// SetOnTowersChanged sets the callback for when the set of tower-occupied tiles changes
+func (tm *TowerManager) SetOnTowersChanged(cb func()) {
+	tm.onTowersChanged = cb
 }
 
 // DrawPlacementIndicator renders the tower image following the cursor
-func (tm *TowerManager) DrawPlacementIndicator(screen *ebiten.Image, params RenderParams, selectedTowerID int, level *TilemapJSON) {
-	if selectedTowerID > 0 && selectedTowerID < len(tm.towers) {
+func (tm *TowerManager) DrawPlacementIndicator(screen *ebiten.Image, params RenderParams, selectedTowerID int, level *tiled.TilemapJSON) {
+	if towerImg := tm.getTowerImage(selectedTowerID); towerImg != nil {
 		mouseX, mouseY := ebiten.CursorPosition()
 		ebiten.SetCursorMode(ebiten.CursorModeHidden)
 
-		towerImg := tm.towers[selectedTowerID]
-		if towerImg != nil && level != nil { // Ensure level is not nil
-			gridX, gridY := tm.screenToGrid(mouseX, mouseY, params)
-			// Corrected placement check:
-			//canPlace := tm.isTileBuildable(gridX, gridY, level) && !tm.isTowerAtLocation(gridX, gridY)
-			canPlace := tm.isTileBuildable(gridX, gridY, level)
+		if level != nil { // Ensure level is not nil
+			gridX, gridY := params.View.ScreenToTile(float64(mouseX), float64(mouseY))
+			canPlace := tm.isTileBuildable(gridX, gridY, level) && tm.passesPlacementValidator(gridX, gridY)
 			// World coordinates of the target tile's center
-			tileCenterX_world := float64(gridX*level.TileWidth + level.TileWidth/2)
-			tileCenterY_world := float64(gridY*level.TileHeight + level.TileHeight/2)
+			tileCenterX_world, tileCenterY_world := params.View.TileCenterWorld(gridX, gridY)
 
 			imgUnscaledWidth := float64(towerImg.Bounds().Dx())
 			imgUnscaledHeight := float64(towerImg.Bounds().Dy())
@@ -203,12 +123,14 @@ func (tm *TowerManager) DrawPlacementIndicator(screen *ebiten.Image, params Rend
 			drawX_world := tileCenterX_world - (imgUnscaledWidth / 2)
 			drawY_world := tileCenterY_world - imgUnscaledHeight // Bottom of image at tileCenterY_world
 
+			// Preview the tower's reach before it is bought
+			if def, ok := towerDefinitions[selectedTowerID]; ok {
+				drawRangeCircle(screen, params, float64(gridX)+0.5, float64(gridY)+0.5, def.Range, canPlace)
+			}
+
 			indicatorOpts := &ebiten.DrawImageOptions{}
-			indicatorOpts.GeoM.Scale(params.Scale, params.Scale)
-			indicatorOpts.GeoM.Translate(
-				drawX_world*params.Scale+params.OffsetX,
-				drawY_world*params.Scale+params.OffsetY,
-			)
+			indicatorOpts.GeoM.Translate(drawX_world, drawY_world)
+			params.View.Apply(&indicatorOpts.GeoM)
 
 			if canPlace {
 				indicatorOpts.ColorScale.Scale(0.8, 1.0, 0.8, 0.5) // Greenish tint for valid
@@ -216,7 +138,7 @@ func (tm *TowerManager) DrawPlacementIndicator(screen *ebiten.Image, params Rend
 				indicatorOpts.ColorScale.Scale(1.0, 0.5, 0.5, 0.5) // Reddish tint for invalid
 			}
 			screen.DrawImage(towerImg, indicatorOpts)
-		} else if towerImg != nil && level == nil {
+		} else {
 			// Fallback: Draw at cursor if level info is missing (should not happen in normal flow)
 			indicatorOpts := &ebiten.DrawImageOptions{}
 			indicatorOpts.GeoM.Scale(params.Scale, params.Scale)
@@ -229,83 +151,79 @@ func (tm *TowerManager) DrawPlacementIndicator(screen *ebiten.Image, params Rend
 	}
 }
 
-// screenToGrid converts screen coordinates to grid coordinates
-func (tm *TowerManager) screenToGrid(screenX, screenY int, params RenderParams) (int, int) {
-	const tileSize = 64
-
-	// Convert screen position to map-relative position
-	mapX := float64(screenX) - params.OffsetX
-	mapY := float64(screenY) - params.OffsetY
-
-	// Convert to grid coordinates
-	gridX := int(mapX / (float64(tileSize) * params.Scale))
-	gridY := int(mapY / (float64(tileSize) * params.Scale))
-
-	return gridX, gridY
-}
-
 // isTileBuildable checks if a tile at given grid coordinates is buildable
-func (tm *TowerManager) isTileBuildable(col, row int, level *TilemapJSON) bool {
+func (tm *TowerManager) isTileBuildable(col, row int, level *tiled.TilemapJSON) bool {
 	// Check if position is within map bounds
-	if col < 0 || row < 0 {
+	if !level.InBounds(col, row) {
 		return false
 	}
-	//Inner bounds checked ok, now check outer bounds
-	layer := level.Layers[0]
-	if col >= layer.Width || row >= layer.Height {
+	// Check if there's already a tower at this position
+	if tm.IsTowerAt(col, row) {
 		return false
 	}
-	// Check if there's already a tower at this position
+
+	// Everything else comes from the Tiled properties baked when the level loaded
+	return level.IsBuildable(col, row)
+}
+
+// IsTowerAt reports whether a tower occupies the tile, including towers still being built
+func (tm *TowerManager) IsTowerAt(col, row int) bool {
 	for _, placedTower := range tm.placedTowers {
 		if placedTower.X == col && placedTower.Y == row {
-			return false
+			return true
 		}
 	}
+	for _, ba := range tm.buildingAnimations {
+		if ba.X == col && ba.Y == row {
+			return true
+		}
+	}
+	return false
+}
 
-	// Water Tiles, and Details Tiles and Road Tiles should be un-buildable.
-	//So any tiles that aren't empty on those two layers should return false
-	//All other tiles (grass) are buildable
-	for _, layer := range level.Layers {
-		index := row*layer.Width + col
-		if index >= 0 && index < len(layer.Data) {
-			tileID := layer.Data[index]
-
-			// Skip empty tiles
-			if tileID == 0 {
-				continue
-			}
-
-			// Remove flip flags to get actual tile ID
-			actualTileID := tileID &^ flipMask
-
-			// Check if it's a water tile (cannot place towers on water)
-			if actualTileID >= waterFirstTileIDLocal {
-				return false
-			}
+// passesPlacementValidator runs the scene's extra placement rule, if one was set
+func (tm *TowerManager) passesPlacementValidator(col, row int) bool {
+	return tm.placementValidator == nil || tm.placementValidator(col, row)
+}
 
-			// Check if it's a details layer tile (cannot place towers on details)
-			if layer.Name == "details" {
-				return false
-			}
-		}
+// BuildInstantly places a finished tower for free, skipping the build animation.
+// The tile must still be buildable. It is used by the developer console.
+func (tm *TowerManager) BuildInstantly(col, row, towerID int, level *tiled.TilemapJSON) error {
+	if _, ok := towerDefinitions[towerID]; !ok {
+		return fmt.Errorf("unknown tower type %d", towerID)
+	}
+	if !tm.isTileBuildable(col, row, level) || !tm.passesPlacementValidator(col, row) {
+		return fmt.Errorf("can't build on %d,%d", col, row)
 	}
+	tower := tm.placeTower(col, row, towerID)
+	tm.events.TowerBuilt.Publish(TowerBuilt{ID: tower.ID, TowerID: towerID, X: col, Y: row})
+	if tm.onTowersChanged != nil {
+		tm.onTowersChanged()
+	}
+	return nil
+}
 
-	return true
+// TrayIcon returns the tray sprite for a tower ID; 0 is the "none" entry
+func (tm *TowerManager) TrayIcon(towerID int) *ebiten.Image {
+	if towerID == 0 {
+		return art.NoneIndicator
+	}
+	return tm.getTowerImage(towerID)
 }
 
 func (tm *TowerManager) getTowerImage(towerID int) *ebiten.Image {
 	switch towerID {
 	case BallistaTowerID:
-		return assets.BallistaTower
+		return art.BallistaTower
 	case MagicTowerID:
-		return assets.MagicTower
+		return art.MagicTower
 	default:
 		return nil
 	}
 }
 
 // HandleTowerPlacement handles clicks on the map to place towers
-func (tm *TowerManager) HandleTowerPlacement(selectedTowerID int, level *TilemapJSON, params RenderParams, currentGold *int) {
+func (tm *TowerManager) HandleTowerPlacement(selectedTowerID int, level *tiled.TilemapJSON, params RenderParams, currentGold *int) {
 	if selectedTowerID == 0 || level == nil { // No tower selected or level is nil
 		return
 	}
@@ -313,25 +231,24 @@ func (tm *TowerManager) HandleTowerPlacement(selectedTowerID int, level *Tilemap
 	mouseX, mouseY := ebiten.CursorPosition()
 
 	// Convert screen coordinates to map grid coordinates
-	// Use level.TileWidth and level.TileHeight for tile dimensions
-	gridX := int((float64(mouseX) - params.OffsetX) / (float64(level.TileWidth) * params.Scale))
-	gridY := int((float64(mouseY) - params.OffsetY) / (float64(level.TileHeight) * params.Scale))
-
-	// Check if click is within map bounds (considering tray)
-	mapPixelWidth := float64(level.Layers[0].Width*level.TileWidth) * params.Scale
-	mapPixelHeight := float64(level.Layers[0].Height*level.TileHeight) * params.Scale
-
-	if float64(mouseX) < params.OffsetX || float64(mouseX) > params.OffsetX+mapPixelWidth ||
-		float64(mouseY) < params.OffsetY || float64(mouseY) > params.OffsetY+mapPixelHeight {
+	gridX, gridY, onMap := params.View.TileAtScreen(mouseX, mouseY)
+	if !onMap {
 		return // Click is outside the map area
 	}
+	if float64(mouseX) >= params.TrayX {
+		return // Click is on the tray, which covers the map when zoomed in
+	}
 
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		if tm.isTileBuildable(gridX, gridY, level) { //&& !tm.isTowerAtLocation(gridX, gridY) {
+		if tm.isTileBuildable(gridX, gridY, level) && tm.passesPlacementValidator(gridX, gridY) {
 
-			if *currentGold >= towerCost {
-				*currentGold -= towerCost
+			cost := towerDefinitions[selectedTowerID].Cost
+			if *currentGold >= cost {
+				*currentGold -= cost
 				tm.startBuildingAnimation(gridX, gridY, selectedTowerID)
+				if tm.onTowersChanged != nil {
+					tm.onTowersChanged()
+				}
 				return //Tower placement animation started!  This is the good case
 			}
 			return //Not enough gold
@@ -339,76 +256,113 @@ func (tm *TowerManager) HandleTowerPlacement(selectedTowerID int, level *Tilemap
 	}
 }
 
+// HandleTowerSelling sells the placed tower under the cursor on right click
+func (tm *TowerManager) HandleTowerSelling(level *tiled.TilemapJSON, params RenderParams, currentGold *int) {
+	if level == nil || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonRight) {
+		return
+	}
+
+	mouseX, mouseY := ebiten.CursorPosition()
+	gridX, gridY, onMap := params.View.TileAtScreen(mouseX, mouseY)
+	if !onMap || float64(mouseX) >= params.TrayX {
+		return
+	}
+
+	for i, tower := range tm.placedTowers {
+		if tower.X == gridX && tower.Y == gridY {
+			*currentGold += tm.sellTower(i)
+			return
+		}
+	}
+}
+
+// sellTower removes a placed tower, returning its sprites to the pool, and
+// returns the refund
+func (tm *TowerManager) sellTower(i int) int {
+	tower := tm.placedTowers[i]
+	if tower.ID == tm.inspectedTowerID {
+		tm.inspectedTowerID = 0
+	}
+	tm.sprites.Put(tower.FiringAnimation)
+	tm.sprites.Put(tower.IdleAnimation)
+	last := len(tm.placedTowers) - 1
+	copy(tm.placedTowers[i:], tm.placedTowers[i+1:])
+	tm.placedTowers[last] = PlacedTower{} // Drop the stale copy's pooled sprites
+	tm.placedTowers = tm.placedTowers[:last]
+	if tm.onTowersChanged != nil {
+		tm.onTowersChanged()
+	}
+	return towerDefinitions[tower.TowerID].SellRefund()
+}
+
 // startBuildingAnimation starts the building animation for a tower at the specified grid position
 func (tm *TowerManager) startBuildingAnimation(col, row int, towerID int) {
-	// Create a new building animation state
-	buildingAnimation := &BuildingAnimationState{
+	// Add a building animation state, playing a sprite from the pool
+	tm.buildingAnimations = append(tm.buildingAnimations, BuildingAnimationState{
 		X:                col,
 		Y:                row,
 		TowerIDToPlace:   towerID,
 		Stage:            StageBuilding,
-		CurrentAnimation: NewAnimatedSprite(assets.TowerBuildAnimation, 1.0, false), // 1 second for build animation
-	}
-
-	// Start the animation
-	buildingAnimation.CurrentAnimation.Play()
-
-	// Add to the building animations slice
-	tm.buildingAnimations = append(tm.buildingAnimations, buildingAnimation)
+		CurrentAnimation: tm.sprites.Get(art.TowerBuild),
+	})
 }
 
-func (tm *TowerManager) placeTower(col, row int, towerID int) {
+// placeTower adds a finished tower and returns it, or nil for an unknown tower type.
+// The pointer is only good until the next tower is placed.
+func (tm *TowerManager) placeTower(col, row int, towerID int) *PlacedTower {
 	towerImg := tm.getTowerImage(towerID)
 	if towerImg == nil {
-		return // Invalid tower ID
+		return nil // Invalid tower ID
 	}
+	def := towerDefinitions[towerID]
 	newTower := PlacedTower{
 		Image:           towerImg,
 		X:               col,
 		Y:               row,
+		ID:              tm.nextTowerID,
 		TowerID:         towerID,
-		Damage:          50,
+		Level:           1,
+		Damage:          def.Damage,
+		FireDelay:       def.FireDelay,
+		Range:           def.Range,
+		SplashRadius:    def.SplashRadius,
+		TargetingMode:   TargetNearest,
 		WeaponAngle:     -math.Pi / 2, // Initialize weapon angle to North (upwards)
 		FireTimer:       0.0,          // Ready to fire immediately
 		FiringAnimation: nil,          // No firing animation initially
 		IdleAnimation:   nil,          // No idle animation initially
 		WeaponFired:     false,        // Initialize WeaponFired to false
 	} // If it's BallistaTower (ID from ballista_tower.go), add its static weapon image
-	if towerID == BallistaTowerID && len(assets.BallistaWeaponFire) > 0 {
-		newTower.WeaponImage = assets.BallistaWeaponFire[0]
+	if towerID == BallistaTowerID && len(art.BallistaFire.Frames) > 0 {
+		newTower.WeaponImage = art.BallistaFire.Frames[0]
 	}
 
 	// If it's Magic Tower (ID from magic_tower.go), add its idle animation and initial weapon image
-	if towerID == MagicTowerID && len(assets.MagicTowerWeaponIdleAnimation) > 0 {
-		newTower.WeaponImage = assets.MagicTowerWeaponIdleAnimation[0]
-		// Create and start the looping idle animation
-		newTower.IdleAnimation = NewAnimatedSprite(assets.MagicTowerWeaponIdleAnimation, 1.0, true) // 1 second duration, looping
-		newTower.IdleAnimation.Play()
+	if towerID == MagicTowerID && len(art.MagicIdle.Frames) > 0 {
+		newTower.WeaponImage = art.MagicIdle.Frames[0]
+		// Start the looping idle animation
+		newTower.IdleAnimation = tm.sprites.Get(art.MagicIdle)
 	}
 
+	tm.nextTowerID++
 	tm.placedTowers = append(tm.placedTowers, newTower)
+	return &tm.placedTowers[len(tm.placedTowers)-1]
 }
 
-func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderParams, level *TilemapJSON) {
-	// STEP 1: Safety check - ensure we have valid map data
-	if level == nil { // Guard against nil level
-		return
-	}
-
-	// STEP 2: Iterate through all placed towers and render each one
+func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderParams) {
+	// STEP 1: Iterate through all placed towers and render each one
 	for _, tower := range tm.placedTowers {
 		// Skip towers with missing sprites (safety check)
 		if tower.Image == nil {
 			continue
 		}
 
-		// STEP 3: COORDINATE CONVERSION - Grid to World
+		// STEP 2: COORDINATE CONVERSION - Grid to World
 		// Convert the tower's grid position to world coordinates
 		// We use the tile center as our reference point for consistent positioning
-		tileCenterX := float64(tower.X*level.TileWidth + level.TileWidth/2)
-		tileCenterY := float64(tower.Y*level.TileHeight + level.TileHeight/2)
+		tileCenterX, tileCenterY := params.View.TileCenterWorld(tower.X, tower.Y)
 
-		// STEP 4: TOWER BASE SPRITE POSITIONING
+		// STEP 3: TOWER BASE SPRITE POSITIONING
 		// Get the dimensions of the tower sprite
 		towerImg := tower.Image
 		imgWidth := float64(towerImg.Bounds().Dx())
@@ -422,22 +376,19 @@ func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderPara
 		drawX_world := tileCenterX - (imgWidth / 2.0) // Center horizontally
 		drawY_world := tileCenterY - imgHeight        // Bottom-align on tile center
 
-		// STEP 5: RENDER THE TOWER BASE
+		// STEP 4: RENDER THE TOWER BASE
 		// Create transformation matrix for the tower base sprite
 		opts := &ebiten.DrawImageOptions{}
-		opts.GeoM.Scale(params.Scale, params.Scale) // Apply camera zoom
-		opts.GeoM.Translate(                        // Convert to screen coordinates
-			drawX_world*params.Scale+params.OffsetX, // World to screen X
-			drawY_world*params.Scale+params.OffsetY, // World to screen Y
-		)
+		opts.GeoM.Translate(drawX_world, drawY_world) // Position in world coordinates
+		params.View.Apply(&opts.GeoM)                 // Convert to screen coordinates
 
 		// Draw the tower base sprite
 		screen.DrawImage(tower.Image, opts)
 
-		// STEP 6: WEAPON RENDERING (if tower has a weapon)
+		// STEP 5: WEAPON RENDERING (if tower has a weapon)
 		// Weapons are optional overlay sprites that can be static or animated
 		if tower.WeaponImage != nil {
-			// STEP 6a: DETERMINE WHICH WEAPON SPRITE TO USE
+			// STEP 5a: DETERMINE WHICH WEAPON SPRITE TO USE
 			// The weapon sprite depends on the tower's current state:
 			// - Firing animation (if actively shooting)
 			// - Idle animation (if has animated weapon when not shooting)
@@ -454,19 +405,15 @@ func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderPara
 				weaponImg = tower.WeaponImage
 			}
 
-			// STEP 6b: RENDER THE WEAPON (if we have a valid sprite)
+			// STEP 5b: RENDER THE WEAPON (if we have a valid sprite)
 			if weaponImg != nil {
 				// Get weapon sprite dimensions
 				w, h := weaponImg.Bounds().Dx(), weaponImg.Bounds().Dy()
 
-				// Calculate tower center for weapon positioning reference
-				tileCenterX := float64(tower.X*level.TileWidth + level.TileWidth/2)
-				tileCenterY := float64(tower.Y*level.TileHeight + level.TileHeight/2)
-
 				// Create transformation matrix for weapon positioning
 				optsWeapon := &ebiten.DrawImageOptions{}
 
-				// STEP 6c: TOWER-TYPE-SPECIFIC WEAPON POSITIONING
+				// STEP 5c: TOWER-TYPE-SPECIFIC WEAPON POSITIONING
 				// Different tower types position their weapons differently:
 				if tower.TowerID == MagicTowerID {
 					// MAGIC TOWER WEAPON POSITIONING:
@@ -478,12 +425,11 @@ func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderPara
 					weaponBottomY_local := float64(h)       // Weapon's bottom edge Y
 
 					// Calculate tower's top-left corner in world coordinates
-					towerTopLeftX := float64(tower.X * level.TileWidth)
-					towerTopLeftY := float64(tower.Y * level.TileHeight)
+					towerTopLeftX, towerTopLeftY := params.View.TileToWorld(float64(tower.X), float64(tower.Y))
 
 					// Position weapon at fixed offset from tower (magic orb positioning)
-					weaponAnchorX_world := towerTopLeftX + 32.0 // 32 pixels right of tower corner
-					weaponAnchorY_world := towerTopLeftY        // At tower's top edge
+					weaponAnchorX_world := towerTopLeftX + params.View.TileWidth/2 // Centred over the tile
+					weaponAnchorY_world := towerTopLeftY                           // At tower's top edge
 
 					// Apply transformations:
 					// 1. Move weapon's anchor point (center-bottom) to origin for positioning
@@ -515,10 +461,9 @@ func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderPara
 					optsWeapon.GeoM.Translate(towerBaseCenterX_world, towerBaseCenterY_world)
 				}
 
-				// STEP 6d: APPLY GLOBAL TRANSFORMATIONS
+				// STEP 5d: APPLY GLOBAL TRANSFORMATIONS
 				// Convert weapon from world coordinates to screen coordinates
-				optsWeapon.GeoM.Scale(params.Scale, params.Scale)         // Apply camera zoom
-				optsWeapon.GeoM.Translate(params.OffsetX, params.OffsetY) // Apply camera offset
+				params.View.Apply(&optsWeapon.GeoM) // Apply camera zoom and offset
 
 				// Draw the weapon sprite
 				screen.DrawImage(weaponImg, optsWeapon)
@@ -527,38 +472,42 @@ func (tm *TowerManager) DrawPlacedTowers(screen *ebiten.Image, params RenderPara
 	}
 }
 
-// UpdateBuildingAnimations updates all towers currently in their build/transition animation
+// UpdateBuildingAnimations updates all towers currently in their build/transition
+// animation. Finished ones are dropped by compacting the slice in place and their
+// sprites go back to the pool, so building doesn't allocate.
 func (tm *TowerManager) UpdateBuildingAnimations(deltaTime float64) {
-	updatedAnimations := make([]*BuildingAnimationState, 0, len(tm.buildingAnimations))
-	for _, ba := range tm.buildingAnimations {
+	kept := 0
+	for i := range tm.buildingAnimations {
+		ba := &tm.buildingAnimations[i]
 		if ba.CurrentAnimation != nil {
 			ba.CurrentAnimation.Update(deltaTime)
-			if !ba.CurrentAnimation.IsPlaying() {
-				if ba.Stage == StageBuilding {
-					// Transition to the next animation
-					ba.Stage = StageTransitioning
-					// Assuming animationLength of 0.75 second for transition, adjust as needed
-					ba.CurrentAnimation = NewAnimatedSprite(assets.TowerTransitionAnimation, 0.75, false) // Adjusted duration
-					ba.CurrentAnimation.Play()
-					updatedAnimations = append(updatedAnimations, ba) // Keep it for next stage
-				} else if ba.Stage == StageTransitioning {
-					// Animation finished, place the actual tower
-					tm.placeTower(ba.X, ba.Y, ba.TowerIDToPlace)
-					// Do not add to updatedAnimations, effectively removing it
-				}
-			} else {
-				updatedAnimations = append(updatedAnimations, ba) // Animation still playing
+			if ba.CurrentAnimation.IsPlaying() {
+				tm.buildingAnimations[kept] = *ba // Animation still playing
+				kept++
+				continue
 			}
+			tm.sprites.Put(ba.CurrentAnimation)
+			ba.CurrentAnimation = nil
+		}
+
+		// The stage finished, or had no frames to play
+		if ba.Stage == StageBuilding {
+			// Transition to the next animation
+			ba.Stage = StageTransitioning
+			ba.CurrentAnimation = tm.sprites.Get(art.TowerTransition)
+			tm.buildingAnimations[kept] = *ba // Keep it for next stage
+			kept++
+		} else if tower := tm.placeTower(ba.X, ba.Y, ba.TowerIDToPlace); tower != nil {
+			// Animation finished, place the actual tower
+			tm.events.TowerBuilt.Publish(TowerBuilt{ID: tower.ID, TowerID: tower.TowerID, X: tower.X, Y: tower.Y})
 		}
 	}
-	tm.buildingAnimations = updatedAnimations
+	clear(tm.buildingAnimations[kept:]) // Let go of the sprites the dropped copies point at
+	tm.buildingAnimations = tm.buildingAnimations[:kept]
 }
 
 // DrawBuildingAnimations draws all towers currently in their build/transition animation
-func (tm *TowerManager) DrawBuildingAnimations(screen *ebiten.Image, params RenderParams, level *TilemapJSON) {
-	if level == nil { // Guard against nil level
-		return
-	}
+func (tm *TowerManager) DrawBuildingAnimations(screen *ebiten.Image, params RenderParams) {
 	const finalTowerSpriteHeight = 128.0 // Height of the final tower sprites
 
 	for _, ba := range tm.buildingAnimations {
@@ -570,8 +519,7 @@ func (tm *TowerManager) DrawBuildingAnimations(screen *ebiten.Image, params Rend
 				imgHeight := float64(frame.Bounds().Dy()) // Animation frame height
 
 				// Calculate the center of the target grid cell in world coordinates
-				tileCenterX := float64(ba.X*level.TileWidth + level.TileWidth/2)
-				tileCenterY := float64(ba.Y*level.TileHeight + level.TileHeight/2)
+				tileCenterX, tileCenterY := params.View.TileCenterWorld(ba.X, ba.Y)
 
 				// Calculate screenX to center the animation frame horizontally on the tile's center.
 				screenX := tileCenterX - (imgWidth / 2.0)
@@ -580,8 +528,8 @@ func (tm *TowerManager) DrawBuildingAnimations(screen *ebiten.Image, params Rend
 				// with the visual center of the final tower sprite.
 				screenY := tileCenterY - (finalTowerSpriteHeight+imgHeight)/2.0
 
-				opts.GeoM.Scale(params.Scale, params.Scale)
-				opts.GeoM.Translate(screenX*params.Scale+params.OffsetX, screenY*params.Scale+params.OffsetY)
+				opts.GeoM.Translate(screenX, screenY)
+				params.View.Apply(&opts.GeoM)
 				screen.DrawImage(frame, opts)
 			}
 		}
@@ -589,7 +537,8 @@ func (tm *TowerManager) DrawBuildingAnimations(screen *ebiten.Image, params Rend
 }
 
 // UpdatePlacedTowers updates the state of all placed towers, including weapon rotation and firing.
-func (tm *TowerManager) UpdatePlacedTowers(deltaTime float64, activeCreeps []*Creep) {
+// Targets and projectile hits are looked up in the creep grid, which must be current.
+func (tm *TowerManager) UpdatePlacedTowers(deltaTime float64, creeps *CreepGrid) {
 	for i := range tm.placedTowers {
 		tower := &tm.placedTowers[i]
 
@@ -609,6 +558,7 @@ func (tm *TowerManager) UpdatePlacedTowers(deltaTime float64, activeCreeps []*Cr
 				if tower.WeaponFired {
 					tm.spawnProjectileFromTower(tower)
 				}
+				tm.sprites.Put(tower.FiringAnimation)
 				tower.FiringAnimation = nil // Animation finished
 			}
 		}
@@ -622,38 +572,25 @@ func (tm *TowerManager) UpdatePlacedTowers(deltaTime float64, activeCreeps []*Cr
 			continue // No weapon to rotate or fire
 		}
 
-		var nearestCreep *Creep
-		minDistSq := math.MaxFloat64 // Use MaxFloat64 instead of -1.0
-
 		// Tower's center in tile coordinates
 		towerCenterX := float64(tower.X) + 0.5 // Add 0.5 to get center of tile
 		towerCenterY := float64(tower.Y) + 0.5
 
-		for _, creep := range activeCreeps {
-			if creep == nil || !creep.IsActive() {
-				continue
-			}
-
-			// Creep coordinates are already in tile coordinates
-			creepX := creep.X
-			creepY := creep.Y
-
-			dx := creepX - towerCenterX
-			dy := creepY - towerCenterY
-			distSq := dx*dx + dy*dy
-
-			if distSq < minDistSq {
-				minDistSq = distSq
-				nearestCreep = creep
-			}
+		// Pick a target according to the tower's targeting mode
+		var targetCreep *Creep
+		var distance float64
+		targetCreep, distance, tm.inRange = selectTarget(tower, creeps, tm.inRange)
+		tower.TargetCreepID = 0
+		if targetCreep != nil {
+			tower.TargetCreepID = targetCreep.ID
 		}
 
-		if nearestCreep != nil {
+		if targetCreep != nil {
 			// Skip weapon rotation for Magic Tower (it should remain stationary)
 			if tower.TowerID != MagicTowerID {
 				// Calculate angle to target (both in tile coordinates)
-				dx := nearestCreep.X - towerCenterX
-				dy := nearestCreep.Y - towerCenterY
+				dx := targetCreep.X - towerCenterX
+				dy := targetCreep.Y - towerCenterY
 				targetAngle := math.Atan2(dy, dx)
 
 				currentAngle := tower.WeaponAngle
@@ -697,22 +634,22 @@ func (tm *TowerManager) UpdatePlacedTowers(deltaTime float64, activeCreeps []*Cr
 			}
 
 			// Check if creep is within firing range and tower can fire
-			distance := math.Sqrt(minDistSq)
-			if distance <= towerRange && tower.FireTimer <= 0 {
+			if distance <= tower.Range && tower.FireTimer <= 0 {
 				// Fire the weapon with target information
-				tm.fireTowerWeapon(tower, nearestCreep)
+				tm.fireTowerWeapon(tower, targetCreep)
 			}
 		}
 	}
 	// Update projectiles
-	tm.projectileManager.Update(deltaTime, activeCreeps)
+	tm.projectileManager.Update(deltaTime, creeps)
 
 }
 
 // fireTowerWeapon handles firing a tower's weapon
 func (tm *TowerManager) fireTowerWeapon(tower *PlacedTower, targetCreep *Creep) {
 	// Set the fire timer to prevent immediate refiring
-	tower.FireTimer = fireDelay
+	tower.FireTimer = tower.FireDelay
+	tm.events.TowerFired.Publish(TowerFired{ID: tower.ID, TowerID: tower.TowerID})
 
 	// Store target position for magic tower projectile targeting
 	if targetCreep != nil {
@@ -723,9 +660,9 @@ func (tm *TowerManager) fireTowerWeapon(tower *PlacedTower, targetCreep *Creep)
 	// Start firing animation based on tower type
 	if tower.TowerID == BallistaTowerID {
 		// Create and start ballista weapon fire animation
-		if len(assets.BallistaWeaponFire) > 0 {
-			tower.FiringAnimation = NewAnimatedSprite(assets.BallistaWeaponFire, fireAnimationDuration, false)
-			tower.FiringAnimation.Play()
+		if len(art.BallistaFire.Frames) > 0 {
+			tm.sprites.Put(tower.FiringAnimation) // In case the last one hasn't finished
+			tower.FiringAnimation = tm.sprites.Get(art.BallistaFire)
 
 			// Spawn projectile when animation finishes - we'll implement this later
 			// Store the tower reference to spawn projectile when animation finishes
@@ -733,9 +670,9 @@ func (tm *TowerManager) fireTowerWeapon(tower *PlacedTower, targetCreep *Creep)
 		}
 	} else if tower.TowerID == MagicTowerID {
 		// Create and start Magic Tower weapon fire animation
-		if len(assets.MagicTowerWeaponAttackAnimation) > 0 {
-			tower.FiringAnimation = NewAnimatedSprite(assets.MagicTowerWeaponAttackAnimation, fireAnimationDuration, false)
-			tower.FiringAnimation.Play()
+		if len(art.MagicAttack.Frames) > 0 {
+			tm.sprites.Put(tower.FiringAnimation) // In case the last one hasn't finished
+			tower.FiringAnimation = tm.sprites.Get(art.MagicAttack)
 
 			// Store the tower reference to spawn projectile when animation finishes
 			tower.WeaponFired = true
@@ -776,7 +713,7 @@ func (tm *TowerManager) spawnProjectileFromTower(tower *PlacedTower) {
 
 	// Final projectile spawn position (weapon tip position)
 	spawnX := towerCenterX + weaponOffsetX
-	spawnY := towerCenterY + weaponOffsetY - 1.0 // Move spawn up by 64 pixels (1 tile)
+	spawnY := towerCenterY + weaponOffsetY - 1.0 // Move spawn up by 1 tile
 
 	// Use weapon angle for projectile direction
 	var angle float64
@@ -790,10 +727,149 @@ func (tm *TowerManager) spawnProjectileFromTower(tower *PlacedTower) {
 	}
 
 	// Spawn projectile
-	tm.projectileManager.SpawnProjectile(spawnX, spawnY, angle, tower.TowerID)
+	tm.projectileManager.SpawnProjectile(spawnX, spawnY, angle, tower.TowerID, tower.ID, tower.Damage, tower.SplashRadius)
+}
+
+// recordHit credits a projectile hit to the tower that fired it
+func (tm *TowerManager) recordHit(hit CreepDamaged) {
+	for i := range tm.placedTowers {
+		tower := &tm.placedTowers[i]
+		if tower.ID != hit.SourceTowerID {
+			continue
+		}
+		tower.DamageDealt += hit.Damage
+		if hit.Killed {
+			tower.Kills++
+		}
+		return
+	}
+	// The tower was sold while its projectile was in flight; nothing to credit
+}
+
+// InspectedTower returns the tower shown in the info panel, or nil
+func (tm *TowerManager) InspectedTower() *PlacedTower {
+	if tm.inspectedTowerID == 0 {
+		return nil
+	}
+	for i := range tm.placedTowers {
+		if tm.placedTowers[i].ID == tm.inspectedTowerID {
+			return &tm.placedTowers[i]
+		}
+	}
+	return nil
+}
+
+// CycleTargeting switches the inspected tower to its next targeting mode
+func (tm *TowerManager) CycleTargeting() {
+	if tower := tm.InspectedTower(); tower != nil {
+		tower.TargetingMode = tower.TargetingMode.Next()
+	}
+}
+
+// HandleTowerInspection selects the placed tower clicked on the map for the info panel,
+// lets the targeting key (T by default) cycle its targeting mode and Escape close the panel. It only acts while no
+// tower is being placed, and ignores the mouse while it is over the UI.
+func (tm *TowerManager) HandleTowerInspection(selectedTowerID int, params RenderParams, mouseOverUI bool) {
+	if selectedTowerID != 0 {
+		tm.inspectedTowerID = 0
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		tm.inspectedTowerID = 0
+	}
+	if tm.keys.JustPressed(ActionCycleTargeting) {
+		tm.CycleTargeting()
+	}
+
+	if mouseOverUI || !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		return
+	}
+	mouseX, mouseY := ebiten.CursorPosition()
+	gridX, gridY, onMap := params.View.TileAtScreen(mouseX, mouseY)
+	if !onMap || float64(mouseX) >= params.TrayX {
+		return // Clicks on the tray don't change the inspected tower
+	}
+
+	// Clicking empty ground closes the panel
+	tm.inspectedTowerID = 0
+	for _, tower := range tm.placedTowers {
+		if tower.X == gridX && tower.Y == gridY {
+			tm.inspectedTowerID = tower.ID
+			return
+		}
+	}
+}
+
+// DrawInspectedRange draws the range circle of the tower in the info panel
+func (tm *TowerManager) DrawInspectedRange(screen *ebiten.Image, params RenderParams) {
+	if tower := tm.InspectedTower(); tower != nil {
+		drawRangeCircle(screen, params, float64(tower.X)+0.5, float64(tower.Y)+0.5, tower.Range, true)
+	}
 }
 
 // DrawProjectiles renders all active projectiles
-func (tm *TowerManager) DrawProjectiles(screen *ebiten.Image, params RenderParams, level *TilemapJSON) {
-	tm.projectileManager.Draw(screen, params, level)
+func (tm *TowerManager) DrawProjectiles(screen *ebiten.Image, params RenderParams) {
+	tm.projectileManager.Draw(screen, params)
+}
+
+// SavedTowers returns the placed towers for a save. Towers still being built are
+// saved as finished.
+func (tm *TowerManager) SavedTowers() []SavedTower {
+	saved := make([]SavedTower, 0, len(tm.placedTowers)+len(tm.buildingAnimations))
+	for _, tower := range tm.placedTowers {
+		saved = append(saved, SavedTower{
+			X:             tower.X,
+			Y:             tower.Y,
+			TowerID:       tower.TowerID,
+			TargetingMode: tower.TargetingMode,
+			Kills:         tower.Kills,
+			DamageDealt:   tower.DamageDealt,
+		})
+	}
+	for _, building := range tm.buildingAnimations {
+		saved = append(saved, SavedTower{X: building.X, Y: building.Y, TowerID: building.TowerIDToPlace})
+	}
+	return saved
+}
+
+// RestoreTowers replaces every tower, building animation and projectile with the
+// towers from a save. Towers the level wouldn't let the player build, such as
+// ones off the map or on the path in a hand-edited save, are left out.
+func (tm *TowerManager) RestoreTowers(saved []SavedTower, level *tiled.TilemapJSON) {
+	for _, tower := range tm.placedTowers {
+		tm.sprites.Put(tower.FiringAnimation)
+		tm.sprites.Put(tower.IdleAnimation)
+	}
+	for _, building := range tm.buildingAnimations {
+		tm.sprites.Put(building.CurrentAnimation)
+	}
+	clear(tm.placedTowers)
+	tm.placedTowers = tm.placedTowers[:0]
+	clear(tm.buildingAnimations)
+	tm.buildingAnimations = tm.buildingAnimations[:0]
+	tm.projectileManager.Clear()
+	tm.inspectedTowerID = 0
+
+	for _, s := range saved {
+		if _, ok := towerDefinitions[s.TowerID]; !ok {
+			continue // Unknown tower type, e.g. from a newer version
+		}
+		if !tm.isTileBuildable(s.X, s.Y, level) || !tm.passesPlacementValidator(s.X, s.Y) {
+			fmt.Printf("Warning: skipping saved %s at %d,%d, which can't be built on\n", towerDefinitions[s.TowerID].Name, s.X, s.Y)
+			continue
+		}
+		tower := tm.placeTower(s.X, s.Y, s.TowerID)
+		if tower == nil {
+			continue
+		}
+		if s.TargetingMode >= 0 && s.TargetingMode < targetingModeCount {
+			tower.TargetingMode = s.TargetingMode
+		}
+		tower.Kills = s.Kills
+		tower.DamageDealt = s.DamageDealt
+	}
+	if tm.onTowersChanged != nil {
+		tm.onTowersChanged()
+	}
 }
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+// BenchmarkTowerManagerBuildCycle builds a tower through its build and transition
+// animations, sells it once it stands and starts the next, alternating ballistas
+// and magic towers so both weapons' sprites go round the pool
+func BenchmarkTowerManagerBuildCycle(b *testing.B) {
+	useTestArt(b)
+	tm := NewTowerManager(NewEventBus())
+	b.Cleanup(tm.Close)
+	grid := NewCreepGrid()
+
+	towerID := BallistaTowerID
+	benchmarkSteadyState(b, 60*10, func() {
+		if len(tm.placedTowers) > 0 {
+			tm.sellTower(0)
+		}
+		if len(tm.buildingAnimations) == 0 {
+			tm.startBuildingAnimation(4, 4, towerID)
+			towerID = BallistaTowerID + MagicTowerID - towerID
+		}
+		tm.UpdateBuildingAnimations(benchTick)
+		tm.UpdatePlacedTowers(benchTick, grid)
+	})
+}
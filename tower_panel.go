@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Range circle colours
+var (
+	rangeFillColor        = color.RGBA{R: 80, G: 160, B: 255, A: 40}
+	rangeOutlineColor     = color.RGBA{R: 120, G: 190, B: 255, A: 160}
+	rangeInvalidFillColor = color.RGBA{R: 255, G: 80, B: 80, A: 40}
+	rangeInvalidLineColor = color.RGBA{R: 255, G: 110, B: 110, A: 160}
+)
+
+// Tower panel layout, in UI pixels
+const (
+	towerPanelWidth    = 300.0
+	towerPanelMargin   = 16.0 // Gap to the top-right corner of the map
+	towerPanelPadding  = 12.0
+	towerPanelSpacing  = 6.0
+	towerPanelFontSize = 20.0
+)
+
+// drawRangeCircle draws a translucent circle of radius rangeTiles around a tile position
+func drawRangeCircle(screen *ebiten.Image, params RenderParams, tileX, tileY, rangeTiles float64, valid bool) {
+	centerX, centerY := params.View.TileToScreen(tileX, tileY)
+	radius := rangeTiles * params.View.TileWidth * params.View.Scale
+
+	fill, outline := rangeFillColor, rangeOutlineColor
+	if !valid {
+		fill, outline = rangeInvalidFillColor, rangeInvalidLineColor
+	}
+	vector.DrawFilledCircle(screen, float32(centerX), float32(centerY), float32(radius), fill, true)
+	vector.StrokeCircle(screen, float32(centerX), float32(centerY), float32(radius), 2, outline, true)
+}
+
+// TowerPanel shows the live stats of the inspected tower in the top-right corner of the map
+type TowerPanel struct {
+	Root      *Panel
+	title     *Label
+	stats     *Label
+	targeting *Button
+	cycleKey  ebiten.Key // Key named in the targeting tooltip
+}
+
+// NewTowerPanel builds the panel; onCycleTargeting is called when its targeting button is used
+func NewTowerPanel(onCycleTargeting func()) *TowerPanel {
+	title := NewLabel("", towerPanelFontSize)
+	title.Color = uiAccentColor
+	stats := NewLabel("", towerPanelFontSize)
+
+	targeting := NewButton("", onCycleTargeting)
+
+	root := NewPanel(LayoutVertical)
+	root.Anchor = AnchorTopRight
+	root.OffsetX, root.OffsetY = -towerPanelMargin, towerPanelMargin
+	root.Width = towerPanelWidth
+	root.Padding = towerPanelPadding
+	root.Spacing = towerPanelSpacing
+	root.Align = AlignStretch
+	root.Background = uiPanelColor
+	root.Border = uiAccentColor
+	root.Hidden = true
+	root.Add(title, stats, targeting)
+
+	p := &TowerPanel{Root: root, title: title, stats: stats, targeting: targeting}
+	p.setCycleKey(defaultKeyBindings()[ActionCycleTargeting])
+	return p
+}
+
+// setCycleKey names the key that cycles targeting in the targeting button's tooltip
+func (p *TowerPanel) setCycleKey(key ebiten.Key) {
+	p.cycleKey = key
+	p.targeting.Tooltip = &Tooltip{Lines: []string{"Targeting", "Which creep in range to shoot at", fmt.Sprintf("Click or press %s to change", key)}}
+}
+
+// Sync shows a tower's current stats, or hides the panel when tower is nil.
+// cycleKey is the key currently bound to cycling targeting.
+func (p *TowerPanel) Sync(tower *PlacedTower, cycleKey ebiten.Key) {
+	p.Root.Hidden = tower == nil
+	if tower == nil {
+		return
+	}
+	if cycleKey != p.cycleKey {
+		p.setCycleKey(cycleKey)
+	}
+
+	p.title.Text = towerDefinitions[tower.TowerID].Name
+	p.stats.Text = fmt.Sprintf("Level: %d\nDamage: %.0f\nFire rate: %.2f/s\nRange: %.1f tiles\nKills: %d\nDamage dealt: %.0f",
+		tower.Level, tower.Damage, 1/tower.FireDelay, tower.Range, tower.Kills, tower.DamageDealt)
+	p.targeting.Text = fmt.Sprintf("Target: %s", tower.TargetingMode)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"math"
+)
+
+const towerCost = 75 // Default cost to place a tower
+
+// TowerDefinition holds the base stats of a buildable tower type
+type TowerDefinition struct {
+	Name      string
+	Category  string  // Tray tab it is listed under, one of trayCategories
+	Cost      int     // Gold to build
+	Damage    float64 // Damage per projectile hit
+	FireDelay float64 // Seconds between shots
+	Range     float64 // Attack range in tiles
+	// SplashRadius is how far from a hit other creeps take splash damage, in tiles; 0 for none
+	SplashRadius float64
+	FireSound    Sound // Played when it shoots
+}
+
+// splashDamageFactor is the share of a hit's damage dealt to creeps caught in the splash
+const splashDamageFactor = 0.5
+
+// towerDefinitions is keyed by tower ID (the tower's index in the tray)
+var towerDefinitions = map[int]TowerDefinition{
+	BallistaTowerID: {Name: "Ballista", Category: "Ballistic", Cost: towerCost, Damage: 25, FireDelay: fireDelay, Range: towerRange, FireSound: SoundBallistaFire},
+	MagicTowerID:    {Name: "Magic Tower", Category: "Magic", Cost: towerCost, Damage: 20, FireDelay: fireDelay, Range: towerRange, SplashRadius: 1.0, FireSound: SoundMagicFire},
+}
+
+// SellRefund is the gold returned when a tower of this type is sold
+func (d TowerDefinition) SellRefund() int {
+	return d.Cost / 2
+}
+
+// TargetingMode decides which creep in range a tower shoots at
+type TargetingMode int
+
+const (
+	TargetNearest   TargetingMode = iota // Closest to the tower
+	TargetFirst                          // Furthest along its path
+	TargetStrongest                      // Most health left
+	TargetWeakest                        // Least health left
+	targetingModeCount
+)
+
+// String returns the name shown in the tower panel
+func (m TargetingMode) String() string {
+	switch m {
+	case TargetFirst:
+		return "First"
+	case TargetStrongest:
+		return "Strongest"
+	case TargetWeakest:
+		return "Weakest"
+	default:
+		return "Nearest"
+	}
+}
+
+// Next returns the mode after m, wrapping around
+func (m TargetingMode) Next() TargetingMode {
+	return (m + 1) % targetingModeCount
+}
+
+// selectTarget picks the creep the tower should aim at and returns its distance in tiles.
+// Creeps in range are ranked by the tower's targeting mode; when none are in range the
+// tower keeps tracking the nearest one so its weapon is already turned when it arrives.
+// inRange is scratch space for the range query and is returned for reuse.
+func selectTarget(tower *PlacedTower, grid *CreepGrid, inRange []*Creep) (*Creep, float64, []*Creep) {
+	towerCenterX := float64(tower.X) + 0.5
+	towerCenterY := float64(tower.Y) + 0.5
+
+	var best *Creep
+	bestDist, bestScore := 0.0, 0.0
+
+	inRange = grid.AppendInRadius(inRange[:0], towerCenterX, towerCenterY, tower.Range)
+	for _, creep := range inRange {
+		if creep.IsDown() {
+			continue // Killed earlier this tick
+		}
+		dist := math.Hypot(creep.X-towerCenterX, creep.Y-towerCenterY)
+
+		// Higher score wins
+		var score float64
+		switch tower.TargetingMode {
+		case TargetFirst:
+			score = -creep.RemainingPathDistance()
+		case TargetStrongest:
+			score = creep.Health
+		case TargetWeakest:
+			score = -creep.Health
+		default:
+			score = -dist
+		}
+		if best == nil || score > bestScore {
+			best, bestDist, bestScore = creep, dist, score
+		}
+	}
+
+	if best != nil {
+		return best, bestDist, inRange
+	}
+	nearest, nearestDist := grid.Nearest(towerCenterX, towerCenterY)
+	if nearest != nil && nearest.IsDown() {
+		nearest = nil // Rare enough to not be worth searching past
+	}
+	return nearest, nearestDist, inRange
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// Tray layout, in UI pixels. From the top: one tab per category, then the tower
+// slots, then the pager. Each slot is the tower sprite with its name and price
+// underneath; the first slot on every page is the "none" entry that cancels placement.
+const (
+	trayPadding       = 4.0  // Gap between the tray edge and its contents
+	trayTopMargin     = 16.0 // Extra space above the tabs and below the pager
+	trayTabGap        = 4.0  // Space between tabs
+	traySlotHeight    = 168.0
+	traySlotGap       = 12.0 // Space between slots
+	traySectionGap    = 8.0  // Space between the tabs and the first slot
+	trayLabelSize     = 14.0 // Font size of all tray text
+	trayPagerButton   = 34.0 // Width of the page buttons
+	trayPagerLabel    = 36.0 // Width of the page number
+	trayPagerFontSize = 14.0
+)
+
+// trayCategories are the tray tabs; towers list theirs in TowerDefinition.Category
+var trayCategories = []string{"All", "Ballistic", "Magic"}
+
+const trayCategoryAll = 0 // Index of the tab that shows every tower
+
+var trayPriceColor = color.RGBA{R: 255, G: 215, B: 80, A: 255}
+
+// traySlot is one visible tray entry
+type traySlot struct {
+	TowerID int // Which tower it offers, 0 for "none"
+	Hotkey  int // Number key that selects it, 0 if none
+	Button  *IconButton
+}
+
+// TowerTray is the tower selection tray down the right of the screen. It is a
+// widget tree, so what is clickable is exactly what is drawn.
+type TowerTray struct {
+	Root     *Panel
+	tabs     []*Button
+	tabPanel *Panel
+	slotList *Panel
+	pager    *Panel
+	prev     *Button
+	next     *Button
+	pageNum  *Label
+
+	slots         []traySlot
+	category      int
+	page          int
+	pageCount     int
+	towersPerPage int
+
+	icon     func(towerID int) *ebiten.Image // Tray sprite for a tower ID; 0 is "none"
+	onSelect func(towerID int)
+}
+
+// NewTowerTray builds the tray. icon returns the sprite for a tower ID and
+// onSelect is called with the tower ID when a slot is chosen.
+func NewTowerTray(icon func(towerID int) *ebiten.Image, onSelect func(towerID int)) *TowerTray {
+	tt := &TowerTray{category: trayCategoryAll, icon: icon, onSelect: onSelect}
+
+	tabs := NewPanel(LayoutVertical)
+	tabs.Align = AlignStretch
+	tabs.Spacing = trayTabGap
+	tt.tabPanel = tabs
+	for i, name := range trayCategories {
+		category := i
+		tab := NewButton(name, func() { tt.setCategory(category) })
+		tab.FontSize = trayLabelSize
+		tt.tabs = append(tt.tabs, tab)
+		tabs.Add(tab)
+	}
+
+	tt.slotList = NewPanel(LayoutVertical)
+	tt.slotList.Align = AlignStretch
+	tt.slotList.Spacing = traySlotGap
+
+	column := NewPanel(LayoutVertical)
+	column.Align = AlignStretch
+	column.Spacing = traySectionGap
+	column.OffsetY = trayTopMargin
+	column.Add(tabs, tt.slotList)
+
+	tt.prev = NewButton("<", func() { tt.setPage(tt.page - 1) })
+	tt.next = NewButton(">", func() { tt.setPage(tt.page + 1) })
+	tt.pageNum = NewLabel("", trayPagerFontSize)
+	tt.pageNum.Align = text.AlignCenter
+	for _, b := range []*Button{tt.prev, tt.next} {
+		b.FontSize = trayPagerFontSize
+		b.Width = trayPagerButton
+	}
+	tt.pageNum.Width = trayPagerLabel
+	tt.pager = NewPanel(LayoutHorizontal)
+	tt.pager.Align = AlignCenter
+	tt.pager.Anchor = AnchorBottom
+	tt.pager.OffsetY = -trayTopMargin
+	tt.pager.Add(tt.prev, tt.pageNum, tt.next)
+
+	tt.Root = NewPanel(LayoutStack)
+	tt.Root.Image = art.TrayBackground
+	tt.Root.Padding = trayPadding
+	tt.Root.Add(column, tt.pager)
+	return tt
+}
+
+// Layout fits the tray to the right edge of the screen
+func (tt *TowerTray) Layout(params RenderParams) {
+	// Work out how many slots fit between the tabs and the pager
+	scale := params.UIScale
+	_, tabsHeight := tt.tabPanel.Measure(scale)
+	_, pagerHeight := tt.pager.Measure(scale)
+	available := float64(params.ScreenHeight) - tabsHeight - pagerHeight -
+		(2*trayPadding+2*trayTopMargin+traySectionGap)*scale
+	slotsFit := 1
+	if available >= traySlotHeight*scale {
+		slotsFit = int((available-traySlotHeight*scale)/((traySlotHeight+traySlotGap)*scale)) + 1
+	}
+	if towersPerPage := max(slotsFit-1, 1); towersPerPage != tt.towersPerPage {
+		tt.towersPerPage = towersPerPage
+		tt.rebuildSlots()
+	}
+
+	tt.Root.Arrange(UIRect{X: params.TrayX, Y: 0, W: float64(params.TrayWidth), H: float64(params.ScreenHeight)}, scale)
+}
+
+// towerIDs returns the towers in the current category in tray order
+func (tt *TowerTray) towerIDs() []int {
+	ids := make([]int, 0, len(towerDefinitions))
+	for id, def := range towerDefinitions {
+		if tt.category == trayCategoryAll || def.Category == trayCategories[tt.category] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// setCategory switches tab and goes back to the first page
+func (tt *TowerTray) setCategory(category int) {
+	tt.category = category
+	tt.page = 0
+	tt.rebuildSlots()
+}
+
+// setPage turns to a page, clamped to the pages there are
+func (tt *TowerTray) setPage(page int) {
+	tt.page = min(max(page, 0), tt.pageCount-1)
+	tt.rebuildSlots()
+}
+
+// rebuildSlots recreates the slot widgets for the current category and page
+func (tt *TowerTray) rebuildSlots() {
+	ids := tt.towerIDs()
+	perPage := max(tt.towersPerPage, 1)
+	tt.pageCount = max((len(ids)+perPage-1)/perPage, 1)
+	tt.page = min(tt.page, tt.pageCount-1)
+
+	tt.slots = tt.slots[:0]
+	tt.slotList.Clear()
+	tt.addSlot(0, 0)
+	first := tt.page * perPage
+	for i, id := range ids[first:min(first+perPage, len(ids))] {
+		hotkey := 0
+		if i < 9 {
+			hotkey = i + 1
+		}
+		tt.addSlot(id, hotkey)
+	}
+
+	for i, tab := range tt.tabs {
+		tab.Selected = i == tt.category
+	}
+	tt.pager.Hidden = tt.pageCount <= 1
+	tt.prev.Disabled = tt.page == 0
+	tt.next.Disabled = tt.page >= tt.pageCount-1
+	tt.pageNum.Text = fmt.Sprintf("%d/%d", tt.page+1, tt.pageCount)
+}
+
+// addSlot appends a slot for a tower
+func (tt *TowerTray) addSlot(towerID, hotkey int) {
+	button := NewIconButton(tt.icon(towerID), func() { tt.onSelect(towerID) })
+	button.Height = traySlotHeight
+	button.Caption, button.SubCaption = "None", "Esc"
+	if def, ok := towerDefinitions[towerID]; ok {
+		button.Caption, button.SubCaption = def.Name, fmt.Sprintf("%dg", def.Cost)
+	}
+	if hotkey > 0 {
+		button.Badge = fmt.Sprint(hotkey)
+	}
+	tt.slots = append(tt.slots, traySlot{TowerID: towerID, Hotkey: hotkey, Button: button})
+	tt.slotList.Add(button)
+}
+
+// Sync updates prices, affordability, the selected slot and tooltips
+func (tt *TowerTray) Sync(currentGold, selectedTower int) {
+	for _, slot := range tt.slots {
+		button := slot.Button
+		button.Selected = slot.TowerID == selectedTower
+
+		def, isTower := towerDefinitions[slot.TowerID]
+		if !isTower {
+			button.Tooltip = &Tooltip{Lines: []string{"None", "Stop placing towers", "Hotkey: Esc"}}
+			continue
+		}
+
+		// Unaffordable towers are greyed out and their tooltip says how much is missing
+		affordable := currentGold >= def.Cost
+		button.Disabled = !affordable
+		button.SubCaptionFG = trayPriceColor
+		if !affordable {
+			button.SubCaptionFG = uiWarningColor
+		}
+
+		lines := []string{
+			def.Name,
+			fmt.Sprintf("Cost: %d gold", def.Cost),
+			fmt.Sprintf("Damage: %.0f  Rate: %.2f/s", def.Damage, 1/def.FireDelay),
+			fmt.Sprintf("Range: %.1f tiles", def.Range),
+		}
+		if slot.Hotkey > 0 {
+			lines = append(lines, fmt.Sprintf("Hotkey: %d", slot.Hotkey))
+		}
+		warning := ""
+		if !affordable {
+			warning = fmt.Sprintf("Need %d more gold", def.Cost-currentGold)
+		}
+		button.Tooltip = &Tooltip{Lines: lines, Warning: warning}
+	}
+}
+
+// HandleInput turns pages with the mouse wheel over the tray and maps the number
+// keys 1-9 to the tower slots on the current page and Escape to "none"
+func (tt *TowerTray) HandleInput(currentGold int) {
+	mouseX, mouseY := ebiten.CursorPosition()
+	if tt.Root.Bounds().Contains(mouseX, mouseY) {
+		if _, wheelY := ebiten.Wheel(); wheelY < 0 {
+			tt.setPage(tt.page + 1)
+		} else if wheelY > 0 {
+			tt.setPage(tt.page - 1)
+		}
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		tt.onSelect(0)
+		return
+	}
+	for _, slot := range tt.slots {
+		if slot.Hotkey == 0 || !inpututil.IsKeyJustPressed(ebiten.Key1+ebiten.Key(slot.Hotkey-1)) {
+			continue
+		}
+		if def := towerDefinitions[slot.TowerID]; currentGold >= def.Cost {
+			tt.onSelect(slot.TowerID)
+		}
+		return
+	}
+}
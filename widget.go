@@ -0,0 +1,391 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Shared widget colours
+var (
+	uiPanelColor    = color.RGBA{R: 20, G: 20, B: 30, A: 200}
+	uiAccentColor   = color.RGBA{R: 200, G: 180, B: 120, A: 255}
+	uiHighlight     = color.RGBA{R: 255, G: 215, B: 80, A: 255}
+	uiWarningColor  = color.RGBA{R: 255, G: 110, B: 110, A: 255}
+	uiDisabledColor = color.RGBA{R: 110, G: 110, B: 110, A: 255}
+	uiButtonColor   = color.RGBA{R: 30, G: 30, B: 40, A: 220}
+	uiButtonHover   = color.RGBA{R: 50, G: 50, B: 70, A: 230}
+	uiButtonPressed = color.RGBA{R: 15, G: 15, B: 20, A: 240}
+)
+
+// Anchor says where a widget sits inside a stack panel
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// place positions a box of size w x h inside area
+func (a Anchor) place(area UIRect, w, h float64) UIRect {
+	x, y := area.X, area.Y
+	switch a {
+	case AnchorTop, AnchorCenter, AnchorBottom:
+		x += (area.W - w) / 2
+	case AnchorTopRight, AnchorRight, AnchorBottomRight:
+		x += area.W - w
+	}
+	switch a {
+	case AnchorLeft, AnchorCenter, AnchorRight:
+		y += (area.H - h) / 2
+	case AnchorBottomLeft, AnchorBottom, AnchorBottomRight:
+		y += area.H - h
+	}
+	return UIRect{X: x, Y: y, W: w, H: h}
+}
+
+// Widget is one element of a retained UI tree. Widgets keep their state between
+// frames; the game changes their fields when something changes and the UI lays
+// them out, draws them and routes input to them.
+//
+// Sizes and offsets are in UI pixels and are multiplied by the UI scale (the
+// RenderParams UIScale in game) when the tree is arranged.
+type Widget interface {
+	Base() *WidgetBase
+	// Measure returns the size the widget wants, in screen pixels
+	Measure(scale float64) (float64, float64)
+	// Arrange gives the widget its screen rectangle and lays out its children
+	Arrange(area UIRect, scale float64)
+	// Draw renders the widget itself; the UI draws its children afterwards
+	Draw(screen *ebiten.Image, scale float64)
+	Children() []Widget
+}
+
+// WidgetBase holds what every widget has: layout hints, visibility, input
+// handlers and the hover/press/focus state the UI maintains
+type WidgetBase struct {
+	Width, Height    float64 // Fixed size in UI pixels; 0 sizes to content
+	Anchor           Anchor  // Position inside a stack panel
+	OffsetX, OffsetY float64 // Nudge from the anchored position, in UI pixels
+	Hidden           bool    // Hidden widgets take no space and get no input
+	Disabled         bool    // Drawn dimmed and not clickable, but still shows its tooltip
+	Focusable        bool    // Can take keyboard focus (Tab) and be activated with Enter or Space
+	Tooltip          *Tooltip
+	OnClick          func()
+
+	rect    UIRect
+	hovered bool
+	pressed bool
+	focused bool
+}
+
+// Base returns the widget's common state
+func (b *WidgetBase) Base() *WidgetBase {
+	return b
+}
+
+// Bounds returns the screen rectangle the widget was last arranged into
+func (b *WidgetBase) Bounds() UIRect {
+	return b.rect
+}
+
+// Arrange records the widget's rectangle; containers override it to place children
+func (b *WidgetBase) Arrange(area UIRect, scale float64) {
+	b.rect = area
+}
+
+// Children returns nil; containers override it
+func (b *WidgetBase) Children() []Widget {
+	return nil
+}
+
+// size returns the fixed size if set, otherwise the content size, in screen pixels
+func (b *WidgetBase) size(contentW, contentH, scale float64) (float64, float64) {
+	w, h := contentW, contentH
+	if b.Width > 0 {
+		w = b.Width * scale
+	}
+	if b.Height > 0 {
+		h = b.Height * scale
+	}
+	return w, h
+}
+
+// interactive reports whether the widget reacts to the mouse
+func (b *WidgetBase) interactive() bool {
+	return b.OnClick != nil || b.Tooltip != nil || b.Focusable
+}
+
+// Tooltip is text shown next to a widget while the cursor is over it
+type Tooltip struct {
+	Lines   []string // The first line is drawn as a title
+	Warning string   // Optional last line in red, e.g. why a disabled button can't be used
+}
+
+// opaqueWidget is implemented by widgets that swallow clicks even without handlers,
+// so clicks on a panel's background don't fall through to the map
+type opaqueWidget interface {
+	opaque() bool
+}
+
+// UI owns a set of widget trees and the input state shared between them.
+// Roots are drawn in order, so later roots sit on top.
+type UI struct {
+	roots     []Widget
+	scale     float64
+	hovered   Widget
+	pressed   Widget
+	focused   Widget
+	ArrowKeys bool // Arrow keys move focus too (menus; the game uses them for the camera)
+}
+
+// NewUI creates a UI for the given widget trees
+func NewUI(roots ...Widget) *UI {
+	return &UI{roots: roots, scale: 1}
+}
+
+// Focus gives keyboard focus to a widget
+func (u *UI) Focus(w Widget) {
+	if u.focused != nil {
+		u.focused.Base().focused = false
+	}
+	u.focused = w
+	if w != nil {
+		w.Base().focused = true
+	}
+}
+
+// Update routes this frame's mouse and keyboard input to the widgets, which must
+// already be arranged. It returns whether the cursor is over the UI, in which case
+// the game should ignore the mouse.
+func (u *UI) Update(scale float64) bool {
+	u.scale = scale
+	mouseX, mouseY := ebiten.CursorPosition()
+	hovered, overUI := u.hitTest(mouseX, mouseY)
+
+	// Hover
+	if u.hovered != hovered {
+		if u.hovered != nil {
+			u.hovered.Base().hovered = false
+		}
+		if hovered != nil {
+			hovered.Base().hovered = true
+		}
+		u.hovered = hovered
+	}
+
+	// A click is a press and release on the same enabled widget
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && hovered != nil {
+		base := hovered.Base()
+		if !base.Disabled && base.OnClick != nil {
+			u.pressed = hovered
+			base.pressed = true
+		}
+		if base.Focusable && !base.Disabled {
+			u.Focus(hovered)
+		}
+	}
+	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) && u.pressed != nil {
+		pressed := u.pressed
+		pressed.Base().pressed = false
+		u.pressed = nil
+		if pressed == hovered && !pressed.Base().Disabled {
+			pressed.Base().OnClick()
+		}
+	}
+
+	u.updateKeyboard()
+	return overUI
+}
+
+// updateKeyboard moves focus with Tab (and the arrow keys in menus) and activates the
+// focused widget with Enter or Space
+func (u *UI) updateKeyboard() {
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab) && shift,
+		u.ArrowKeys && inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		u.moveFocus(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab),
+		u.ArrowKeys && inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		u.moveFocus(1)
+	}
+
+	if u.focused == nil {
+		return
+	}
+	base := u.focused.Base()
+	if !u.isShown(u.focused) || base.Disabled {
+		return
+	}
+	if base.OnClick != nil && (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace)) {
+		base.OnClick()
+	}
+}
+
+// moveFocus focuses the next (or previous) visible, enabled focusable widget
+func (u *UI) moveFocus(step int) {
+	var focusable []Widget
+	for _, root := range u.roots {
+		walkWidgets(root, func(w Widget) {
+			if w.Base().Focusable && !w.Base().Disabled {
+				focusable = append(focusable, w)
+			}
+		})
+	}
+	if len(focusable) == 0 {
+		return
+	}
+
+	index := -1
+	for i, w := range focusable {
+		if w == u.focused {
+			index = i
+			break
+		}
+	}
+	if index < 0 && step < 0 {
+		index = 0
+	}
+	u.Focus(focusable[(index+step+len(focusable))%len(focusable)])
+}
+
+// isShown reports whether a widget is in one of the trees and not hidden
+func (u *UI) isShown(target Widget) bool {
+	found := false
+	for _, root := range u.roots {
+		walkWidgets(root, func(w Widget) {
+			if w == target {
+				found = true
+			}
+		})
+	}
+	return found
+}
+
+// walkWidgets calls fn for every visible widget in a tree, parents first
+func walkWidgets(w Widget, fn func(Widget)) {
+	if w.Base().Hidden {
+		return
+	}
+	fn(w)
+	for _, child := range w.Children() {
+		walkWidgets(child, fn)
+	}
+}
+
+// hitTest returns the topmost interactive widget under a screen position, and
+// whether the position is over any part of the UI at all
+func (u *UI) hitTest(x, y int) (Widget, bool) {
+	for i := len(u.roots) - 1; i >= 0; i-- {
+		if w, over := hitTestWidget(u.roots[i], x, y); over {
+			return w, true
+		}
+	}
+	return nil, false
+}
+
+func hitTestWidget(w Widget, x, y int) (Widget, bool) {
+	base := w.Base()
+	if base.Hidden {
+		return nil, false
+	}
+	children := w.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		if hit, over := hitTestWidget(children[i], x, y); over {
+			return hit, true
+		}
+	}
+	if !base.rect.Contains(x, y) {
+		return nil, false
+	}
+	if base.interactive() {
+		return w, true
+	}
+	if o, ok := w.(opaqueWidget); ok && o.opaque() {
+		return nil, true
+	}
+	return nil, false
+}
+
+// Draw renders every tree, then the tooltip of the widget under the cursor
+func (u *UI) Draw(screen *ebiten.Image) {
+	for _, root := range u.roots {
+		drawWidget(screen, root, u.scale)
+	}
+	if u.hovered != nil && u.hovered.Base().Tooltip != nil && u.isShown(u.hovered) {
+		u.drawTooltip(screen, u.hovered.Base())
+	}
+}
+
+func drawWidget(screen *ebiten.Image, w Widget, scale float64) {
+	if w.Base().Hidden {
+		return
+	}
+	w.Draw(screen, scale)
+	for _, child := range w.Children() {
+		drawWidget(screen, child, scale)
+	}
+}
+
+// drawTooltip draws a tooltip beside its widget, on whichever side of it has more room
+func (u *UI) drawTooltip(screen *ebiten.Image, base *WidgetBase) {
+	const padding = 8.0
+	const lineHeight = 22.0
+	const fontSize = 16.0
+	const gap = 8.0
+
+	tip := base.Tooltip
+	lines := tip.Lines
+	if tip.Warning != "" {
+		lines = append(lines[:len(lines):len(lines)], tip.Warning)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	scale := u.scale
+	fontFace := uiFont(nil, fontSize, scale)
+	width := 0.0
+	for _, line := range lines {
+		lineWidth, _ := text.Measure(line, fontFace, 0)
+		width = max(width, lineWidth)
+	}
+	width += 2 * padding * scale
+	height := (2*padding + lineHeight*float64(len(lines))) * scale
+
+	screenW, screenH := float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy())
+	x := base.rect.X + base.rect.W + gap*scale
+	if base.rect.X+base.rect.W/2 > screenW/2 {
+		x = base.rect.X - gap*scale - width
+	}
+	_, mouseY := ebiten.CursorPosition()
+	y := min(max(float64(mouseY), 0), screenH-height)
+
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(width), float32(height), uiPanelColor, false)
+	vector.StrokeRect(screen, float32(x), float32(y), float32(width), float32(height), float32(scale), uiAccentColor, false)
+
+	for i, line := range lines {
+		opts := &text.DrawOptions{}
+		opts.GeoM.Translate(x+padding*scale, y+(padding+lineHeight*float64(i))*scale)
+		switch {
+		case i == 0:
+			opts.ColorScale.ScaleWithColor(uiAccentColor)
+		case tip.Warning != "" && i == len(lines)-1:
+			opts.ColorScale.ScaleWithColor(uiWarningColor)
+		default:
+			opts.ColorScale.ScaleWithColor(color.White)
+		}
+		text.Draw(screen, line, fontFace, opts)
+	}
+}
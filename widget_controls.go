@@ -0,0 +1,288 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// lineSpacing is the distance between lines of text, relative to the font size
+const lineSpacing = 1.3
+
+// Label is a piece of text; it may span several lines
+type Label struct {
+	WidgetBase
+	Text     string
+	FontSize float64 // In UI pixels
+	Color    color.Color
+	Font     *text.GoTextFaceSource // nil for the bold UI font
+	Align    text.Align             // Horizontal alignment inside the label's rectangle
+}
+
+// NewLabel creates a white label
+func NewLabel(txt string, fontSize float64) *Label {
+	return &Label{Text: txt, FontSize: fontSize, Color: color.White}
+}
+
+// Measure returns the size of the text
+func (l *Label) Measure(scale float64) (float64, float64) {
+	face := uiFont(l.Font, l.FontSize, scale)
+	w, h := text.Measure(l.Text, face, face.Size*lineSpacing)
+	return l.size(w, h, scale)
+}
+
+// Draw renders the text, vertically centred in the label's rectangle
+func (l *Label) Draw(screen *ebiten.Image, scale float64) {
+	face := uiFont(l.Font, l.FontSize, scale)
+	_, textH := text.Measure(l.Text, face, face.Size*lineSpacing)
+
+	opts := &text.DrawOptions{}
+	opts.LineSpacing = face.Size * lineSpacing
+	opts.PrimaryAlign = l.Align
+	x := l.rect.X
+	switch l.Align {
+	case text.AlignCenter:
+		x += l.rect.W / 2
+	case text.AlignEnd:
+		x += l.rect.W
+	}
+	opts.GeoM.Translate(x, l.rect.Y+(l.rect.H-textH)/2)
+
+	clr := l.Color
+	if l.Disabled {
+		clr = uiDisabledColor
+	}
+	opts.ColorScale.ScaleWithColor(clr)
+	text.Draw(screen, l.Text, face, opts)
+}
+
+// Button is a clickable, focusable text button
+type Button struct {
+	WidgetBase
+	Text     string
+	FontSize float64
+	Selected bool // Drawn with a highlighted border, e.g. the active tab
+}
+
+// NewButton creates a button that calls onClick when clicked or activated from the keyboard
+func NewButton(txt string, onClick func()) *Button {
+	b := &Button{Text: txt, FontSize: 18}
+	b.OnClick = onClick
+	b.Focusable = true
+	return b
+}
+
+// Measure returns the size of the text plus a margin
+func (b *Button) Measure(scale float64) (float64, float64) {
+	const marginX, marginY = 12.0, 6.0
+	face := uiFont(nil, b.FontSize, scale)
+	w, h := text.Measure(b.Text, face, 0)
+	return b.size(w+2*marginX*scale, h+2*marginY*scale, scale)
+}
+
+// Draw renders the button in its current state
+func (b *Button) Draw(screen *ebiten.Image, scale float64) {
+	r := b.rect
+	fill := uiButtonColor
+	switch {
+	case b.Disabled:
+	case b.pressed:
+		fill = uiButtonPressed
+	case b.hovered:
+		fill = uiButtonHover
+	}
+	vector.DrawFilledRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), fill, false)
+	if b.Selected || b.focused {
+		vector.StrokeRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), float32(2*scale), uiHighlight, false)
+	}
+
+	face := uiFont(nil, b.FontSize, scale)
+	_, textH := text.Measure(b.Text, face, 0)
+	opts := &text.DrawOptions{}
+	opts.PrimaryAlign = text.AlignCenter
+	opts.GeoM.Translate(r.X+r.W/2, r.Y+(r.H-textH)/2)
+	if b.Disabled {
+		opts.ColorScale.ScaleWithColor(uiDisabledColor)
+	} else {
+		opts.ColorScale.ScaleWithColor(color.White)
+	}
+	text.Draw(screen, b.Text, face, opts)
+}
+
+// IconButton is a clickable image with up to two caption lines under it and
+// an optional badge (such as a hotkey) in its top-left corner
+type IconButton struct {
+	WidgetBase
+	Image        *ebiten.Image
+	Caption      string
+	SubCaption   string
+	SubCaptionFG color.Color // Colour of the second caption line
+	Badge        string
+	FontSize     float64
+	Selected     bool // Drawn with a highlighted border
+}
+
+// NewIconButton creates an icon button that calls onClick when clicked
+func NewIconButton(img *ebiten.Image, onClick func()) *IconButton {
+	b := &IconButton{Image: img, FontSize: 14, SubCaptionFG: color.White}
+	b.OnClick = onClick
+	return b
+}
+
+// captionLines returns how many caption lines the button shows
+func (b *IconButton) captionLines() int {
+	switch {
+	case b.SubCaption != "":
+		return 2
+	case b.Caption != "":
+		return 1
+	}
+	return 0
+}
+
+// Measure returns the image size plus the caption lines
+func (b *IconButton) Measure(scale float64) (float64, float64) {
+	w, h := 0.0, 0.0
+	if b.Image != nil {
+		w = float64(b.Image.Bounds().Dx()) * scale
+		h = float64(b.Image.Bounds().Dy()) * scale
+	}
+	h += float64(b.captionLines()) * b.FontSize * lineSpacing * scale
+	return b.size(w, h, scale)
+}
+
+// Draw renders the icon, captions, badge and selection border
+func (b *IconButton) Draw(screen *ebiten.Image, scale float64) {
+	r := b.rect
+	centerX := r.X + r.W/2
+	y := r.Y
+
+	if b.Image != nil {
+		imgW := float64(b.Image.Bounds().Dx()) * scale
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(scale, scale)
+		opts.GeoM.Translate(centerX-imgW/2, y)
+		if b.Disabled {
+			opts.ColorScale.Scale(0.35, 0.35, 0.35, 1) // Greyed out
+		} else if b.hovered {
+			opts.ColorScale.Scale(1.15, 1.15, 1.15, 1)
+		}
+		screen.DrawImage(b.Image, opts)
+		y += float64(b.Image.Bounds().Dy()) * scale
+	}
+
+	face := uiFont(nil, b.FontSize, scale)
+	drawLine := func(line string, clr color.Color, x, y float64, align text.Align) {
+		opts := &text.DrawOptions{}
+		opts.PrimaryAlign = align
+		opts.GeoM.Translate(x, y)
+		opts.ColorScale.ScaleWithColor(clr)
+		text.Draw(screen, line, face, opts)
+	}
+	if b.Caption != "" {
+		drawLine(b.Caption, color.White, centerX, y, text.AlignCenter)
+		y += b.FontSize * lineSpacing * scale
+	}
+	if b.SubCaption != "" {
+		drawLine(b.SubCaption, b.SubCaptionFG, centerX, y, text.AlignCenter)
+	}
+	if b.Badge != "" {
+		drawLine(b.Badge, color.White, r.X+2*scale, r.Y, text.AlignStart)
+	}
+
+	if b.Selected || b.focused {
+		const borderInset = 4.0
+		vector.StrokeRect(screen, float32(r.X), float32(r.Y-borderInset*scale), float32(r.W), float32(r.H+2*borderInset*scale),
+			float32(2*scale), uiHighlight, false)
+	}
+}
+
+// ProgressBar shows a value between 0 and 1. With a FillImage it is drawn as
+// Segments image pieces between optional end caps; otherwise as a plain bar.
+type ProgressBar struct {
+	WidgetBase
+	Value                 float64
+	Segments              int
+	LeftImage, RightImage *ebiten.Image
+	FillImage             *ebiten.Image
+	FillTint              ebiten.ColorScale // Applied to filled segments
+	FillColor, EmptyColor color.Color       // Plain bar colours
+}
+
+// NewProgressBar creates a plain bar of the given size in UI pixels
+func NewProgressBar(width, height float64) *ProgressBar {
+	p := &ProgressBar{
+		Value:      1,
+		FillColor:  color.RGBA{R: 200, G: 40, B: 40, A: 255},
+		EmptyColor: color.RGBA{R: 40, G: 40, B: 40, A: 160},
+	}
+	p.Width, p.Height = width, height
+	return p
+}
+
+// NewSegmentedBar creates a bar made of image segments between two end caps
+func NewSegmentedBar(left, fill, right *ebiten.Image, segments int) *ProgressBar {
+	return &ProgressBar{Value: 1, Segments: segments, LeftImage: left, FillImage: fill, RightImage: right}
+}
+
+// Measure returns the size of the caps and segments, or the fixed size
+func (p *ProgressBar) Measure(scale float64) (float64, float64) {
+	if p.FillImage == nil {
+		return p.size(0, 0, scale)
+	}
+	w, h := 0.0, 0.0
+	for _, img := range []*ebiten.Image{p.LeftImage, p.RightImage} {
+		if img != nil {
+			w += float64(img.Bounds().Dx())
+			h = max(h, float64(img.Bounds().Dy()))
+		}
+	}
+	w += float64(p.Segments * p.FillImage.Bounds().Dx())
+	h = max(h, float64(p.FillImage.Bounds().Dy()))
+	return p.size(w*scale, h*scale, scale)
+}
+
+// Draw renders the bar
+func (p *ProgressBar) Draw(screen *ebiten.Image, scale float64) {
+	value := min(max(p.Value, 0), 1)
+	r := p.rect
+	if p.FillImage == nil {
+		vector.DrawFilledRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), p.EmptyColor, false)
+		vector.DrawFilledRect(screen, float32(r.X), float32(r.Y), float32(r.W*value), float32(r.H), p.FillColor, false)
+		return
+	}
+
+	// Convert the value to a number of filled segments, rounding down
+	filledSegments := int(value * float64(p.Segments))
+
+	// Track the current X position as we draw each piece from left to right
+	currentX := r.X
+	drawPiece := func(img *ebiten.Image, colorScale *ebiten.ColorScale) {
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(scale, scale)
+		opts.GeoM.Translate(currentX, r.Y)
+		if colorScale != nil {
+			opts.ColorScale = *colorScale
+		}
+		screen.DrawImage(img, opts)
+		currentX += float64(img.Bounds().Dx()) * scale
+	}
+
+	if p.LeftImage != nil {
+		drawPiece(p.LeftImage, nil)
+	}
+	var empty ebiten.ColorScale
+	empty.Scale(0.2, 0.2, 0.2, 0.6) // Lost segments are dark grey and see-through
+	for i := 0; i < p.Segments; i++ {
+		if i < filledSegments {
+			drawPiece(p.FillImage, &p.FillTint)
+		} else {
+			drawPiece(p.FillImage, &empty)
+		}
+	}
+	if p.RightImage != nil {
+		drawPiece(p.RightImage, nil)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Layout is how a panel places its children
+type Layout int
+
+const (
+	LayoutStack      Layout = iota // Each child at its Anchor, on top of each other
+	LayoutVertical                 // Children in a column, top to bottom
+	LayoutHorizontal               // Children in a row, left to right
+)
+
+// Align is where children sit across a row or column. In a stack, AlignStretch
+// makes children as wide as the panel.
+type Align int
+
+const (
+	AlignStart Align = iota
+	AlignCenter
+	AlignEnd
+	AlignStretch
+)
+
+// Panel is a container with an optional background that lays out its children
+type Panel struct {
+	WidgetBase
+	Layout     Layout
+	Align      Align
+	Spacing    float64       // Gap between children in a row or column, in UI pixels
+	Padding    float64       // Gap between the panel edge and its children, in UI pixels
+	Background color.Color   // Fill colour; nil for none
+	Border     color.Color   // Outline colour; nil for none
+	Image      *ebiten.Image // Stretched over the panel instead of the fill colour
+
+	children []Widget
+}
+
+// NewPanel creates an empty panel with the given layout
+func NewPanel(layout Layout) *Panel {
+	return &Panel{Layout: layout}
+}
+
+// Add appends children to the panel
+func (p *Panel) Add(children ...Widget) {
+	p.children = append(p.children, children...)
+}
+
+// Clear removes all children
+func (p *Panel) Clear() {
+	p.children = nil
+}
+
+// Children returns the panel's children
+func (p *Panel) Children() []Widget {
+	return p.children
+}
+
+// opaque makes panels with a background catch clicks
+func (p *Panel) opaque() bool {
+	return p.Background != nil || p.Image != nil
+}
+
+// Measure returns the size of the children plus padding
+func (p *Panel) Measure(scale float64) (float64, float64) {
+	contentW, contentH := 0.0, 0.0
+	shown := 0
+	for _, child := range p.children {
+		if child.Base().Hidden {
+			continue
+		}
+		w, h := child.Measure(scale)
+		switch p.Layout {
+		case LayoutVertical:
+			contentW = max(contentW, w)
+			contentH += h
+		case LayoutHorizontal:
+			contentW += w
+			contentH = max(contentH, h)
+		default:
+			b := child.Base()
+			contentW = max(contentW, w+abs(b.OffsetX)*scale)
+			contentH = max(contentH, h+abs(b.OffsetY)*scale)
+		}
+		shown++
+	}
+	if shown > 1 {
+		gaps := float64(shown-1) * p.Spacing * scale
+		switch p.Layout {
+		case LayoutVertical:
+			contentH += gaps
+		case LayoutHorizontal:
+			contentW += gaps
+		}
+	}
+	padding := 2 * p.Padding * scale
+	return p.size(contentW+padding, contentH+padding, scale)
+}
+
+// Arrange places the children inside the panel's padding
+func (p *Panel) Arrange(area UIRect, scale float64) {
+	p.rect = area
+	content := area.Inset(p.Padding * scale)
+	x, y := content.X, content.Y
+
+	for _, child := range p.children {
+		b := child.Base()
+		if b.Hidden {
+			continue
+		}
+		w, h := child.Measure(scale)
+		var r UIRect
+		switch p.Layout {
+		case LayoutVertical:
+			cx, cw := alignSpan(p.Align, content.X, content.W, w)
+			r = UIRect{X: cx, Y: y, W: cw, H: h}
+			y += h + p.Spacing*scale
+		case LayoutHorizontal:
+			cy, ch := alignSpan(p.Align, content.Y, content.H, h)
+			r = UIRect{X: x, Y: cy, W: w, H: ch}
+			x += w + p.Spacing*scale
+		default:
+			if p.Align == AlignStretch {
+				w = content.W
+			}
+			r = b.Anchor.place(content, w, h)
+		}
+		r.X += b.OffsetX * scale
+		r.Y += b.OffsetY * scale
+		child.Arrange(r, scale)
+	}
+}
+
+// alignSpan positions a child of the given size across a row or column
+func alignSpan(align Align, start, available, size float64) (float64, float64) {
+	switch align {
+	case AlignCenter:
+		return start + (available-size)/2, size
+	case AlignEnd:
+		return start + available - size, size
+	case AlignStretch:
+		return start, available
+	default:
+		return start, size
+	}
+}
+
+// Draw renders the panel background and border
+func (p *Panel) Draw(screen *ebiten.Image, scale float64) {
+	r := p.rect
+	if p.Image != nil {
+		bounds := p.Image.Bounds()
+		opts := &ebiten.DrawImageOptions{}
+		opts.GeoM.Scale(r.W/float64(bounds.Dx()), r.H/float64(bounds.Dy()))
+		opts.GeoM.Translate(r.X, r.Y)
+		screen.DrawImage(p.Image, opts)
+	} else if p.Background != nil {
+		vector.DrawFilledRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), p.Background, false)
+	}
+	if p.Border != nil {
+		vector.StrokeRect(screen, float32(r.X), float32(r.Y), float32(r.W), float32(r.H), float32(2*scale), p.Border, false)
+	}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}